@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+
+	json "github.com/json-iterator/go"
+)
+
+const blueGreenVersionFormat = "20060102150405"
+
+// Alias represents an alias and the properties it has for a specific index.
+type Alias struct {
+	Name          string         `json:"name"`
+	Index         string         `json:"index"`
+	Filter        map[string]any `json:"filter,omitempty"`
+	IndexRouting  string         `json:"index_routing,omitempty"`
+	SearchRouting string         `json:"search_routing,omitempty"`
+	IsWriteIndex  bool           `json:"is_write_index,omitempty"`
+}
+
+// String returns a string representation of the Alias.
+func (a *Alias) String() string {
+	return string(anchor.ToJSONFormatted(a))
+}
+
+// BlueGreenResult is a container for the result of a blue/green index cutover.
+type BlueGreenResult struct {
+	Index     string   `json:"index"`
+	Previous  []string `json:"previous,omitempty"`
+	Reindexed int      `json:"reindexed"`
+	Deleted   bool     `json:"deleted"`
+}
+
+// String returns a string representation of the BlueGreenResult.
+func (r *BlueGreenResult) String() string {
+	return string(anchor.ToJSONFormatted(r))
+}
+
+// AliasService provides operations for managing OpenSearch index aliases.
+type AliasService struct {
+	client *opensearch.Client
+}
+
+// Aliases returns the AliasService for the Repository.
+func (r *Repository) Aliases() *AliasService {
+	return &AliasService{client: r.client}
+}
+
+// List returns the aliases for the provided indices, or for all indices if none are provided.
+func (s *AliasService) List(ctx context.Context, indices ...string) ([]*Alias, error) {
+	names, _ := indexNames(indices...)
+	return s.get(ctx, opensearchapi.IndicesGetAliasRequest{Index: names})
+}
+
+// Get returns the index-specific properties for each of the provided alias names. Aliases that do not exist are
+// omitted from the result.
+func (s *AliasService) Get(ctx context.Context, aliases ...string) ([]*Alias, error) {
+	names, err := indexNames(aliases...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.get(ctx, opensearchapi.IndicesGetAliasRequest{Name: names})
+}
+
+// Add adds the alias to the index using the provided options.
+func (s *AliasService) Add(ctx context.Context, index string, alias string, options ...func(*AliasAction)) error {
+	return s.Update(ctx, AddAlias(index, alias, options...))
+}
+
+// Remove removes the alias from the index.
+func (s *AliasService) Remove(ctx context.Context, index string, alias string) error {
+	return s.Update(ctx, RemoveAlias(index, alias))
+}
+
+// Update performs the provided alias actions as a single atomic operation.
+func (s *AliasService) Update(ctx context.Context, actions ...*AliasAction) error {
+	if len(actions) == 0 {
+		return ErrInvalid
+	}
+
+	for _, a := range actions {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
+
+	body := anchor.ToJSON(map[string]any{"actions": actions})
+
+	log.Debug(fmt.Sprintf("[opensearch] updating aliases:\n%s", anchor.ToJSONFormatted(actions)))
+
+	return do(ctx, s.client, "update aliases", opensearchapi.IndicesUpdateAliasesRequest{
+		Body: bytes.NewReader(body),
+	}, nil)
+}
+
+// BlueGreen performs a zero-downtime cutover to a new versioned index named `<index>-<version>`.
+//
+// The new index is created using the options set with WithNewIndexOptions and, if enabled with WithReindexPrevious,
+// populated with the documents from the indices currently referenced by the read alias. The read and write aliases are
+// then moved to the new index in a single atomic update so that readers and writers never observe a missing alias.
+// The reindex is executed asynchronously on the cluster and its task is waited on using TaskService.Wait. If any step
+// fails after the new index has been created, the new index is deleted before the error is returned.
+func (s *AliasService) BlueGreen(ctx context.Context, index string, options ...func(*BlueGreenOption)) (*BlueGreenResult, error) {
+	index = strings.TrimSpace(index)
+	if index == "" {
+		return nil, ErrMalformedIndex
+	}
+
+	opts := &BlueGreenOption{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.readAlias == "" && opts.writeAlias == "" {
+		return nil, fmt.Errorf("opensearch: read or write alias is required for blue/green cutover: %w", ErrInvalid)
+	}
+
+	if opts.version == "" {
+		opts.version = time.Now().UTC().Format(blueGreenVersionFormat)
+	}
+
+	log.Info(fmt.Sprintf("[opensearch] performing blue/green cutover with options:\n%s", opts),
+		log.String("index", index))
+
+	result := &BlueGreenResult{Index: index + "-" + opts.version}
+
+	held, err := s.indicesFor(ctx, opts.readAlias, opts.writeAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := sortedKeys(held)
+	for _, p := range previous {
+		if p == result.Index {
+			return nil, fmt.Errorf("opensearch: index %s is already referenced by alias: %w", p, ErrExists)
+		}
+	}
+	result.Previous = previous
+
+	indexService := &IndexService{client: s.client}
+	if err := indexService.Create(ctx, result.Index, opts.indexOptions...); err != nil {
+		return nil, err
+	}
+
+	if err := s.cutover(ctx, result, held, opts); err != nil {
+		// The aliases are moved in a single atomic update as the last step, so the new index is not yet referenced
+		// by either alias and can be removed without affecting readers or writers.
+		if err := indexService.Delete(context.WithoutCancel(ctx), result.Index); err != nil {
+			log.Error("[opensearch] could not delete index of failed blue/green cutover", log.Err(err),
+				log.String("index", result.Index))
+		}
+		return nil, err
+	}
+
+	log.Info(fmt.Sprintf("[opensearch] completed blue/green cutover:\n%s", result))
+
+	return result, nil
+}
+
+// cutover populates the new index of the blue/green cutover if enabled with WithReindexPrevious, and moves the
+// aliases from the previous indices to the new index.
+func (s *AliasService) cutover(ctx context.Context, result *BlueGreenResult, held map[string][]string, opts *BlueGreenOption) error {
+	if opts.reindexPrevious && opts.readAlias != "" {
+		sources, err := s.indicesFor(ctx, opts.readAlias)
+		if err != nil {
+			return err
+		}
+
+		if len(sources) > 0 {
+			request, err := prepareReindex(strings.Join(sortedKeys(sources), ","), result.Index, WithReindexRefresh(true))
+			if err != nil {
+				return err
+			}
+
+			waitForCompletion := false
+			request.WaitForCompletion = &waitForCompletion
+
+			task, err := (&TaskService{client: s.client}).submit(ctx, "reindex", request)
+			if err != nil {
+				return err
+			}
+
+			status, err := task.Wait(ctx)
+			if err != nil {
+				return err
+			}
+			result.Reindexed = status.Progress.Created + status.Progress.Updated
+		}
+	}
+
+	var actions []*AliasAction
+	if opts.readAlias != "" {
+		actions = append(actions, AddAlias(result.Index, opts.readAlias))
+	}
+
+	if opts.writeAlias != "" {
+		actions = append(actions, AddAlias(result.Index, opts.writeAlias, WithAliasWriteIndex(true)))
+	}
+
+	var deleted bool
+	for _, p := range result.Previous {
+		if opts.deletePrevious && opts.readAlias != "" && slices.Contains(held[p], opts.readAlias) {
+			actions = append(actions, RemoveIndex(p))
+			deleted = true
+			continue
+		}
+
+		for _, a := range held[p] {
+			actions = append(actions, RemoveAlias(p, a))
+		}
+	}
+
+	if err := s.Update(ctx, actions...); err != nil {
+		return err
+	}
+	result.Deleted = deleted
+	return nil
+}
+
+// get returns the aliases matching the request. A request for multiple alias names where only some exist responds
+// with 404, but still contains the aliases that were found, so those are returned instead of an error. An error
+// wrapping ErrNotFound is only returned if an index of the request does not exist.
+func (s *AliasService) get(ctx context.Context, request opensearchapi.IndicesGetAliasRequest) ([]*Alias, error) {
+	response, err := request.Do(ctx, s.client)
+	if err != nil {
+		return nil, fmt.Errorf("opensearch: error executing get aliases request: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		if err := Body.Close(); err != nil {
+			log.Error("[opensearch]", log.Err(err))
+		}
+	}(response.Body)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("opensearch: could not read get aliases response body: %w", err)
+	}
+
+	var e map[string]json.RawMessage
+	if response.IsError() {
+		// A missing alias is reported with a string error alongside the aliases that were found, whereas a missing
+		// index is reported with an error object.
+		if response.StatusCode != http.StatusNotFound || json.Unmarshal(body, &e) != nil || !isString(e["error"]) {
+			response.Body = io.NopCloser(bytes.NewReader(body))
+			return nil, responseError("get aliases", response)
+		}
+		delete(e, "error")
+		delete(e, "status")
+	} else if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("opensearch: could not decode get aliases response body: %w", err)
+	}
+
+	var aliases []*Alias
+	for index, raw := range e {
+		var v struct {
+			Aliases map[string]Alias `json:"aliases"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode aliases for index %s: %w", index, err)
+		}
+
+		for name, a := range v.Aliases {
+			a.Name = name
+			a.Index = index
+			aliases = append(aliases, &a)
+		}
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		if aliases[i].Name == aliases[j].Name {
+			return aliases[i].Index < aliases[j].Index
+		}
+		return aliases[i].Name < aliases[j].Name
+	})
+	return aliases, nil
+}
+
+// indicesFor returns the names of the indices referenced by the provided aliases, mapped to the sorted names of the
+// provided aliases each index holds.
+func (s *AliasService) indicesFor(ctx context.Context, aliases ...string) (map[string][]string, error) {
+	var names []string
+	for _, a := range aliases {
+		if a != "" {
+			names = append(names, a)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	a, err := s.Get(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make(map[string][]string)
+	for _, alias := range a {
+		if !slices.Contains(indices[alias.Index], alias.Name) {
+			indices[alias.Index] = append(indices[alias.Index], alias.Name)
+		}
+	}
+
+	for _, held := range indices {
+		sort.Strings(held)
+	}
+	return indices, nil
+}
+
+// isString returns whether the raw JSON value is a string.
+func isString(raw json.RawMessage) bool {
+	return len(raw) > 0 && raw[0] == '"'
+}
@@ -10,3 +10,8 @@ package bandaid
 // 2022-06-30
 //   - Added Cluster requests for creating and retrieving data streams
 //   - Added Cluster update request to fix https://github.com/opensearch-project/opensearch-go/issues/132 for version 2.0
+//
+// 2026-10-18
+//   - Added Cluster request for force merging indices with support for `wait_for_completion`
+//   - Added Cluster requests for deleting data streams and retrieving data stream statistics
+//   - Added requests for managing Index State Management (ISM) policies
@@ -0,0 +1,187 @@
+package bandaid
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// Adapted from the opensearch-go cluster library for the Index State Management (ISM) plugin.
+//
+// See: https://opensearch.org/docs/latest/im-plugin/ism/api/
+
+func newISMAddPolicyFunc(t opensearchapi.Transport) ISMAddPolicy {
+	return func(index []string, body io.Reader, o ...func(*ISMAddPolicyRequest)) (*opensearchapi.Response, error) {
+		var r = ISMAddPolicyRequest{Index: index, Body: body}
+		for _, f := range o {
+			f(&r)
+		}
+		return r.Do(r.ctx, t)
+	}
+}
+
+// ----- Cluster Definition -------------------------------------------------------
+
+// ISMAddPolicy - Attaches an ISM policy to indices.
+type ISMAddPolicy func(index []string, body io.Reader, o ...func(*ISMAddPolicyRequest)) (*opensearchapi.Response, error)
+
+// ISMAddPolicyRequest configures the ISM Add Policy request.
+type ISMAddPolicyRequest struct {
+	Index []string
+
+	Body io.Reader
+
+	Pretty     bool
+	Human      bool
+	ErrorTrace bool
+	FilterPath []string
+
+	Header http.Header
+
+	ctx context.Context
+}
+
+// Do executes the request and returns response or error.
+func (r ISMAddPolicyRequest) Do(ctx context.Context, transport opensearchapi.Transport) (*opensearchapi.Response, error) {
+	var (
+		method string
+		path   strings.Builder
+		params map[string]string
+	)
+
+	method = "POST"
+
+	path.Grow(1 + len("_plugins") + 1 + len("_ism") + 1 + len("add") + 1 + len(strings.Join(r.Index, ",")))
+	path.WriteString("/")
+	path.WriteString("_plugins")
+	path.WriteString("/")
+	path.WriteString("_ism")
+	path.WriteString("/")
+	path.WriteString("add")
+	path.WriteString("/")
+	path.WriteString(strings.Join(r.Index, ","))
+
+	params = make(map[string]string)
+
+	if r.Pretty {
+		params["pretty"] = "true"
+	}
+
+	if r.Human {
+		params["human"] = "true"
+	}
+
+	if r.ErrorTrace {
+		params["error_trace"] = "true"
+	}
+
+	if len(r.FilterPath) > 0 {
+		params["filter_path"] = strings.Join(r.FilterPath, ",")
+	}
+
+	req, err := newRequest(method, path.String(), r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(params) > 0 {
+		q := req.URL.Query()
+		for k, v := range params {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	if r.Body != nil {
+		req.Header[headerContentType] = headerContentTypeJSON
+	}
+
+	if len(r.Header) > 0 {
+		if len(req.Header) == 0 {
+			req.Header = r.Header
+		} else {
+			for k, vv := range r.Header {
+				for _, v := range vv {
+					req.Header.Add(k, v)
+				}
+			}
+		}
+	}
+
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	res, err := transport.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := opensearchapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
+
+// WithContext sets the request context.
+func (f ISMAddPolicy) WithContext(v context.Context) func(*ISMAddPolicyRequest) {
+	return func(r *ISMAddPolicyRequest) {
+		r.ctx = v
+	}
+}
+
+// WithPretty makes the response body pretty-printed.
+func (f ISMAddPolicy) WithPretty() func(*ISMAddPolicyRequest) {
+	return func(r *ISMAddPolicyRequest) {
+		r.Pretty = true
+	}
+}
+
+// WithHuman makes statistical values human-readable.
+func (f ISMAddPolicy) WithHuman() func(*ISMAddPolicyRequest) {
+	return func(r *ISMAddPolicyRequest) {
+		r.Human = true
+	}
+}
+
+// WithErrorTrace includes the stack trace for errors in the response body.
+func (f ISMAddPolicy) WithErrorTrace() func(*ISMAddPolicyRequest) {
+	return func(r *ISMAddPolicyRequest) {
+		r.ErrorTrace = true
+	}
+}
+
+// WithFilterPath filters the properties of the response body.
+func (f ISMAddPolicy) WithFilterPath(v ...string) func(*ISMAddPolicyRequest) {
+	return func(r *ISMAddPolicyRequest) {
+		r.FilterPath = v
+	}
+}
+
+// WithHeader adds the headers to the HTTP request.
+func (f ISMAddPolicy) WithHeader(h map[string]string) func(*ISMAddPolicyRequest) {
+	return func(r *ISMAddPolicyRequest) {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		for k, v := range h {
+			r.Header.Add(k, v)
+		}
+	}
+}
+
+// WithOpaqueID adds the X-Opaque-Id header to the HTTP request.
+func (f ISMAddPolicy) WithOpaqueID(s string) func(*ISMAddPolicyRequest) {
+	return func(r *ISMAddPolicyRequest) {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		r.Header.Set("X-Opaque-Id", s)
+	}
+}
@@ -0,0 +1,180 @@
+package bandaid
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// Adapted from the opensearch-go cluster library for the Index State Management (ISM) plugin.
+//
+// See: https://opensearch.org/docs/latest/im-plugin/ism/api/
+
+func newISMExplainFunc(t opensearchapi.Transport) ISMExplain {
+	return func(index []string, o ...func(*ISMExplainRequest)) (*opensearchapi.Response, error) {
+		var r = ISMExplainRequest{Index: index}
+		for _, f := range o {
+			f(&r)
+		}
+		return r.Do(r.ctx, t)
+	}
+}
+
+// ----- Cluster Definition -------------------------------------------------------
+
+// ISMExplain - Gets the current ISM state of indices.
+type ISMExplain func(index []string, o ...func(*ISMExplainRequest)) (*opensearchapi.Response, error)
+
+// ISMExplainRequest configures the ISM Explain Index request.
+type ISMExplainRequest struct {
+	Index []string
+
+	Pretty     bool
+	Human      bool
+	ErrorTrace bool
+	FilterPath []string
+
+	Header http.Header
+
+	ctx context.Context
+}
+
+// Do executes the request and returns response or error.
+func (r ISMExplainRequest) Do(ctx context.Context, transport opensearchapi.Transport) (*opensearchapi.Response, error) {
+	var (
+		method string
+		path   strings.Builder
+		params map[string]string
+	)
+
+	method = "GET"
+
+	path.Grow(1 + len("_plugins") + 1 + len("_ism") + 1 + len("explain") + 1 + len(strings.Join(r.Index, ",")))
+	path.WriteString("/")
+	path.WriteString("_plugins")
+	path.WriteString("/")
+	path.WriteString("_ism")
+	path.WriteString("/")
+	path.WriteString("explain")
+	path.WriteString("/")
+	path.WriteString(strings.Join(r.Index, ","))
+
+	params = make(map[string]string)
+
+	if r.Pretty {
+		params["pretty"] = "true"
+	}
+
+	if r.Human {
+		params["human"] = "true"
+	}
+
+	if r.ErrorTrace {
+		params["error_trace"] = "true"
+	}
+
+	if len(r.FilterPath) > 0 {
+		params["filter_path"] = strings.Join(r.FilterPath, ",")
+	}
+
+	req, err := newRequest(method, path.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(params) > 0 {
+		q := req.URL.Query()
+		for k, v := range params {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	if len(r.Header) > 0 {
+		if len(req.Header) == 0 {
+			req.Header = r.Header
+		} else {
+			for k, vv := range r.Header {
+				for _, v := range vv {
+					req.Header.Add(k, v)
+				}
+			}
+		}
+	}
+
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	res, err := transport.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := opensearchapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
+
+// WithContext sets the request context.
+func (f ISMExplain) WithContext(v context.Context) func(*ISMExplainRequest) {
+	return func(r *ISMExplainRequest) {
+		r.ctx = v
+	}
+}
+
+// WithPretty makes the response body pretty-printed.
+func (f ISMExplain) WithPretty() func(*ISMExplainRequest) {
+	return func(r *ISMExplainRequest) {
+		r.Pretty = true
+	}
+}
+
+// WithHuman makes statistical values human-readable.
+func (f ISMExplain) WithHuman() func(*ISMExplainRequest) {
+	return func(r *ISMExplainRequest) {
+		r.Human = true
+	}
+}
+
+// WithErrorTrace includes the stack trace for errors in the response body.
+func (f ISMExplain) WithErrorTrace() func(*ISMExplainRequest) {
+	return func(r *ISMExplainRequest) {
+		r.ErrorTrace = true
+	}
+}
+
+// WithFilterPath filters the properties of the response body.
+func (f ISMExplain) WithFilterPath(v ...string) func(*ISMExplainRequest) {
+	return func(r *ISMExplainRequest) {
+		r.FilterPath = v
+	}
+}
+
+// WithHeader adds the headers to the HTTP request.
+func (f ISMExplain) WithHeader(h map[string]string) func(*ISMExplainRequest) {
+	return func(r *ISMExplainRequest) {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		for k, v := range h {
+			r.Header.Add(k, v)
+		}
+	}
+}
+
+// WithOpaqueID adds the X-Opaque-Id header to the HTTP request.
+func (f ISMExplain) WithOpaqueID(s string) func(*ISMExplainRequest) {
+	return func(r *ISMExplainRequest) {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		r.Header.Set("X-Opaque-Id", s)
+	}
+}
@@ -0,0 +1,213 @@
+package bandaid
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// Adapted from the opensearch-go cluster library for the Index State Management (ISM) plugin.
+//
+// See: https://opensearch.org/docs/latest/im-plugin/ism/api/
+
+func newISMPutPolicyFunc(t opensearchapi.Transport) ISMPutPolicy {
+	return func(policyID string, body io.Reader, o ...func(*ISMPutPolicyRequest)) (*opensearchapi.Response, error) {
+		var r = ISMPutPolicyRequest{PolicyID: policyID, Body: body}
+		for _, f := range o {
+			f(&r)
+		}
+		return r.Do(r.ctx, t)
+	}
+}
+
+// ----- Cluster Definition -------------------------------------------------------
+
+// ISMPutPolicy - Creates or updates an ISM policy.
+type ISMPutPolicy func(policyID string, body io.Reader, o ...func(*ISMPutPolicyRequest)) (*opensearchapi.Response, error)
+
+// ISMPutPolicyRequest configures the ISM Create or Update Policy request.
+type ISMPutPolicyRequest struct {
+	PolicyID string
+
+	Body io.Reader
+
+	IfPrimaryTerm *int
+	IfSeqNo       *int
+
+	Pretty     bool
+	Human      bool
+	ErrorTrace bool
+	FilterPath []string
+
+	Header http.Header
+
+	ctx context.Context
+}
+
+// Do executes the request and returns response or error.
+func (r ISMPutPolicyRequest) Do(ctx context.Context, transport opensearchapi.Transport) (*opensearchapi.Response, error) {
+	var (
+		method string
+		path   strings.Builder
+		params map[string]string
+	)
+
+	method = "PUT"
+
+	path.Grow(1 + len("_plugins") + 1 + len("_ism") + 1 + len("policies") + 1 + len(r.PolicyID))
+	path.WriteString("/")
+	path.WriteString("_plugins")
+	path.WriteString("/")
+	path.WriteString("_ism")
+	path.WriteString("/")
+	path.WriteString("policies")
+	path.WriteString("/")
+	path.WriteString(r.PolicyID)
+
+	params = make(map[string]string)
+
+	if r.IfPrimaryTerm != nil {
+		params["if_primary_term"] = strconv.FormatInt(int64(*r.IfPrimaryTerm), 10)
+	}
+
+	if r.IfSeqNo != nil {
+		params["if_seq_no"] = strconv.FormatInt(int64(*r.IfSeqNo), 10)
+	}
+
+	if r.Pretty {
+		params["pretty"] = "true"
+	}
+
+	if r.Human {
+		params["human"] = "true"
+	}
+
+	if r.ErrorTrace {
+		params["error_trace"] = "true"
+	}
+
+	if len(r.FilterPath) > 0 {
+		params["filter_path"] = strings.Join(r.FilterPath, ",")
+	}
+
+	req, err := newRequest(method, path.String(), r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(params) > 0 {
+		q := req.URL.Query()
+		for k, v := range params {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	if r.Body != nil {
+		req.Header[headerContentType] = headerContentTypeJSON
+	}
+
+	if len(r.Header) > 0 {
+		if len(req.Header) == 0 {
+			req.Header = r.Header
+		} else {
+			for k, vv := range r.Header {
+				for _, v := range vv {
+					req.Header.Add(k, v)
+				}
+			}
+		}
+	}
+
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	res, err := transport.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := opensearchapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+
+	return &response, nil
+}
+
+// WithContext sets the request context.
+func (f ISMPutPolicy) WithContext(v context.Context) func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		r.ctx = v
+	}
+}
+
+// WithIfPrimaryTerm - only perform the operation if the policy has this primary term.
+func (f ISMPutPolicy) WithIfPrimaryTerm(v int) func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		r.IfPrimaryTerm = &v
+	}
+}
+
+// WithIfSeqNo - only perform the operation if the policy has this sequence number.
+func (f ISMPutPolicy) WithIfSeqNo(v int) func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		r.IfSeqNo = &v
+	}
+}
+
+// WithPretty makes the response body pretty-printed.
+func (f ISMPutPolicy) WithPretty() func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		r.Pretty = true
+	}
+}
+
+// WithHuman makes statistical values human-readable.
+func (f ISMPutPolicy) WithHuman() func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		r.Human = true
+	}
+}
+
+// WithErrorTrace includes the stack trace for errors in the response body.
+func (f ISMPutPolicy) WithErrorTrace() func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		r.ErrorTrace = true
+	}
+}
+
+// WithFilterPath filters the properties of the response body.
+func (f ISMPutPolicy) WithFilterPath(v ...string) func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		r.FilterPath = v
+	}
+}
+
+// WithHeader adds the headers to the HTTP request.
+func (f ISMPutPolicy) WithHeader(h map[string]string) func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		for k, v := range h {
+			r.Header.Add(k, v)
+		}
+	}
+}
+
+// WithOpaqueID adds the X-Opaque-Id header to the HTTP request.
+func (f ISMPutPolicy) WithOpaqueID(s string) func(*ISMPutPolicyRequest) {
+	return func(r *ISMPutPolicyRequest) {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		r.Header.Set("X-Opaque-Id", s)
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/transientvariable/log-go"
+)
+
+// Defaults for the adaptive mode of the BulkIndexer enabled using WithBulkIndexerAdaptive.
+const (
+	DefaultBulkAdaptiveMinFlushSize  = 262_144         // 256KiB
+	DefaultBulkAdaptiveMaxFlushSize  = 20_971_520      // 20MiB
+	DefaultBulkAdaptiveMinWorkers    = 1               // 1
+	DefaultBulkAdaptiveMaxWorkers    = 16              // 16
+	DefaultBulkAdaptiveTargetLatency = 1 * time.Second // 1s
+)
+
+// bulkAdaptive adjusts the flush size and concurrency of a BulkIndexer using additive increase/multiplicative
+// decrease (AIMD) based on the latency and rejections of its bulk requests.
+//
+// A bulk request is overloaded if any of its items is rejected (e.g. status 429), or its latency exceeds the target
+// latency. On overload, the flush size and concurrency are halved, at most once per target latency so that requests
+// already in flight during the same overload do not decrease them repeatedly. Otherwise, once a number of consecutive
+// requests equal to the current concurrency complete without overload, the flush size is increased by the minimum flush
+// size and the concurrency by one.
+type bulkAdaptive struct {
+	flushSize     atomic.Int64
+	healthy       int
+	lastDecrease  time.Time
+	limiter       *bulkLimiter
+	maxFlushSize  int
+	maxWorkers    int
+	minFlushSize  int
+	minWorkers    int
+	mutex         sync.Mutex
+	name          string
+	targetLatency time.Duration
+}
+
+// newBulkAdaptive creates the bulkAdaptive for the provided options, starting at the provided flush size and number of
+// workers clamped to the bounds. An error wrapping ErrInvalid is returned if a lower bound exceeds its upper bound.
+func newBulkAdaptive(opts *BulkIndexerOptions, flushSize int, workers int) (*bulkAdaptive, error) {
+	a := &bulkAdaptive{
+		maxFlushSize:  intValue(opts.adaptiveMaxFlushSize, DefaultBulkAdaptiveMaxFlushSize),
+		maxWorkers:    intValue(opts.adaptiveMaxWorkers, max(workers, DefaultBulkAdaptiveMaxWorkers)),
+		minFlushSize:  intValue(opts.adaptiveMinFlushSize, DefaultBulkAdaptiveMinFlushSize),
+		minWorkers:    intValue(opts.adaptiveMinWorkers, DefaultBulkAdaptiveMinWorkers),
+		name:          opts.name,
+		targetLatency: durationValue(opts.adaptiveTargetLatency, DefaultBulkAdaptiveTargetLatency),
+	}
+
+	if a.minFlushSize > a.maxFlushSize {
+		return nil, fmt.Errorf("opensearch: bulk indexer adaptive min flush size %d exceeds max flush size %d: %w",
+			a.minFlushSize, a.maxFlushSize, ErrInvalid)
+	}
+
+	if a.minWorkers > a.maxWorkers {
+		return nil, fmt.Errorf("opensearch: bulk indexer adaptive min workers %d exceeds max workers %d: %w",
+			a.minWorkers, a.maxWorkers, ErrInvalid)
+	}
+
+	a.flushSize.Store(int64(clamp(flushSize, a.minFlushSize, a.maxFlushSize)))
+	a.limiter = newBulkLimiter(clamp(workers, a.minWorkers, a.maxWorkers))
+	return a, nil
+}
+
+// currentFlushSize returns the current flush size in bytes.
+func (a *bulkAdaptive) currentFlushSize() int {
+	return int(a.flushSize.Load())
+}
+
+// observe adjusts the flush size and concurrency based on the latency of a bulk request and whether any of its items
+// were rejected.
+func (a *bulkAdaptive) observe(latency time.Duration, rejected bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	flushSize, workers := a.currentFlushSize(), a.limiter.currentLimit()
+	if rejected || latency > a.targetLatency {
+		a.healthy = 0
+		if time.Since(a.lastDecrease) < a.targetLatency {
+			return
+		}
+		a.lastDecrease = time.Now()
+		a.update(flushSize/2, workers/2, "decreasing", latency, rejected)
+		return
+	}
+
+	a.healthy++
+	if a.healthy >= workers {
+		a.healthy = 0
+		a.update(flushSize+a.minFlushSize, workers+1, "increasing", latency, rejected)
+	}
+}
+
+func (a *bulkAdaptive) update(flushSize int, workers int, direction string, latency time.Duration, rejected bool) {
+	flushSize = clamp(flushSize, a.minFlushSize, a.maxFlushSize)
+	workers = clamp(workers, a.minWorkers, a.maxWorkers)
+	if flushSize == a.currentFlushSize() && workers == a.limiter.currentLimit() {
+		return
+	}
+
+	a.flushSize.Store(int64(flushSize))
+	a.limiter.setLimit(workers)
+
+	log.Debug("[opensearch] "+direction+" bulk indexer flush size and concurrency",
+		log.String("name", a.name),
+		log.Int("flush_size", flushSize),
+		log.Int("workers", workers),
+		log.String("latency", latency.String()),
+		log.Bool("rejected", rejected))
+}
+
+// bulkLimiter limits the number of workers of a BulkIndexer that concurrently issue bulk requests.
+type bulkLimiter struct {
+	active int
+	cond   *sync.Cond
+	limit  int
+	mutex  sync.Mutex
+}
+
+func newBulkLimiter(limit int) *bulkLimiter {
+	l := &bulkLimiter{limit: limit}
+	l.cond = sync.NewCond(&l.mutex)
+	return l
+}
+
+// acquire blocks until the number of active workers is below the limit.
+func (l *bulkLimiter) acquire() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+}
+
+// release releases the slot obtained using acquire.
+func (l *bulkLimiter) release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.active--
+	l.cond.Broadcast()
+}
+
+func (l *bulkLimiter) currentLimit() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.limit
+}
+
+func (l *bulkLimiter) setLimit(limit int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.limit = limit
+	l.cond.Broadcast()
+}
+
+func clamp(value int, minValue int, maxValue int) int {
+	return max(minValue, min(value, maxValue))
+}
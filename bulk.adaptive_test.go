@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBulkAdaptiveObserve(t *testing.T) {
+	type observation struct {
+		latency  time.Duration
+		rejected bool
+	}
+
+	var (
+		healthy    = observation{latency: time.Millisecond}
+		overloaded = observation{latency: 2 * time.Hour}
+		rejected   = observation{latency: time.Millisecond, rejected: true}
+	)
+
+	tests := []struct {
+		name            string
+		flushSize       int
+		workers         int
+		observations    []observation
+		expectedSize    int
+		expectedWorkers int
+	}{
+		{
+			name:            "starting values clamped",
+			flushSize:       5000,
+			workers:         20,
+			expectedSize:    1000,
+			expectedWorkers: 8,
+		},
+		{
+			name:            "increase after healthy round",
+			flushSize:       200,
+			workers:         2,
+			observations:    []observation{healthy, healthy},
+			expectedSize:    300,
+			expectedWorkers: 3,
+		},
+		{
+			name:            "no increase before healthy round",
+			flushSize:       200,
+			workers:         2,
+			observations:    []observation{healthy},
+			expectedSize:    200,
+			expectedWorkers: 2,
+		},
+		{
+			name:            "increase clamped to max",
+			flushSize:       1000,
+			workers:         8,
+			observations:    []observation{healthy, healthy, healthy, healthy, healthy, healthy, healthy, healthy},
+			expectedSize:    1000,
+			expectedWorkers: 8,
+		},
+		{
+			name:            "decrease on latency",
+			flushSize:       400,
+			workers:         4,
+			observations:    []observation{overloaded},
+			expectedSize:    200,
+			expectedWorkers: 2,
+		},
+		{
+			name:            "decrease on rejection",
+			flushSize:       400,
+			workers:         4,
+			observations:    []observation{rejected},
+			expectedSize:    200,
+			expectedWorkers: 2,
+		},
+		{
+			name:            "decrease at most once per target latency",
+			flushSize:       400,
+			workers:         4,
+			observations:    []observation{rejected, overloaded, rejected},
+			expectedSize:    200,
+			expectedWorkers: 2,
+		},
+		{
+			name:            "decrease clamped to min",
+			flushSize:       150,
+			workers:         1,
+			observations:    []observation{rejected},
+			expectedSize:    100,
+			expectedWorkers: 1,
+		},
+		{
+			name:            "overload resets healthy round",
+			flushSize:       200,
+			workers:         4,
+			observations:    []observation{healthy, healthy, healthy, rejected, healthy},
+			expectedSize:    100,
+			expectedWorkers: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := newBulkAdaptive(&BulkIndexerOptions{
+				adaptiveMinFlushSize:  100,
+				adaptiveMaxFlushSize:  1000,
+				adaptiveMinWorkers:    1,
+				adaptiveMaxWorkers:    8,
+				adaptiveTargetLatency: time.Hour,
+			}, tt.flushSize, tt.workers)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			for _, o := range tt.observations {
+				a.observe(o.latency, o.rejected)
+			}
+
+			if size := a.currentFlushSize(); size != tt.expectedSize {
+				t.Errorf("expected flush size %d, got %d", tt.expectedSize, size)
+			}
+
+			if workers := a.limiter.currentLimit(); workers != tt.expectedWorkers {
+				t.Errorf("expected workers %d, got %d", tt.expectedWorkers, workers)
+			}
+		})
+	}
+}
+
+func TestNewBulkAdaptive(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    *BulkIndexerOptions
+		workers    int
+		maxWorkers int
+		err        error
+	}{
+		{
+			name:       "default max workers",
+			options:    &BulkIndexerOptions{},
+			workers:    2,
+			maxWorkers: DefaultBulkAdaptiveMaxWorkers,
+		},
+		{
+			name:       "default max workers below starting workers",
+			options:    &BulkIndexerOptions{},
+			workers:    DefaultBulkAdaptiveMaxWorkers + 1,
+			maxWorkers: DefaultBulkAdaptiveMaxWorkers + 1,
+		},
+		{
+			name:    "min flush size exceeds max",
+			options: &BulkIndexerOptions{adaptiveMinFlushSize: 1000, adaptiveMaxFlushSize: 100},
+			workers: 2,
+			err:     ErrInvalid,
+		},
+		{
+			name:    "min workers exceeds max",
+			options: &BulkIndexerOptions{adaptiveMinWorkers: 4, adaptiveMaxWorkers: 2},
+			workers: 2,
+			err:     ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := newBulkAdaptive(tt.options, DefaultFlushSize, tt.workers)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error wrapping %v, got: %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if a.maxWorkers != tt.maxWorkers {
+				t.Fatalf("expected max workers %d, got %d", tt.maxWorkers, a.maxWorkers)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"github.com/transientvariable/log-go"
+)
+
+// Enumeration of the modes used by the BulkIndexer for delivering results to the consumer channel and result handler.
+const (
+	// BulkDeliveryBlock delivers each result before the worker that produced it continues, so a slow consumer slows
+	// down indexing.
+	BulkDeliveryBlock = "block"
+
+	// BulkDeliveryBuffer queues results in a bounded buffer, so workers only block when the buffer is full.
+	BulkDeliveryBuffer = "buffer"
+
+	// BulkDeliveryDrop queues results in a bounded buffer and drops results when the buffer is full, which is counted
+	// by BulkIndexerStats.NumDropped. Workers never block on delivery.
+	BulkDeliveryDrop = "drop"
+)
+
+// DefaultBulkDeliveryBufferSize is the default size of the buffer used by BulkDeliveryBuffer and BulkDeliveryDrop.
+const DefaultBulkDeliveryBufferSize = 1024
+
+// bulkDelivery delivers the results produced by BulkIndexer workers to the consumer channel and result handler from a
+// single goroutine, so results are delivered in the order they are produced.
+type bulkDelivery struct {
+	consumer chan<- *BulkIndexerResult
+	done     chan struct{}
+	handler  func(*BulkIndexerResult)
+	mode     string
+	results  chan *BulkIndexerResult
+	stats    *bulkIndexerStats
+}
+
+// newBulkDelivery creates and starts the bulkDelivery for the provided options, or returns nil if neither a consumer
+// channel nor a result handler is configured.
+func newBulkDelivery(opts *BulkIndexerOptions, stats *bulkIndexerStats) *bulkDelivery {
+	if opts.consumer == nil && opts.resultHandler == nil {
+		return nil
+	}
+
+	d := &bulkDelivery{
+		consumer: opts.consumer,
+		done:     make(chan struct{}),
+		handler:  opts.resultHandler,
+		mode:     opts.deliveryMode,
+		stats:    stats,
+	}
+
+	switch d.mode {
+	case BulkDeliveryBuffer, BulkDeliveryDrop:
+		d.results = make(chan *BulkIndexerResult, intValue(opts.deliveryBufferSize, DefaultBulkDeliveryBufferSize))
+	default:
+		d.mode = BulkDeliveryBlock
+		d.results = make(chan *BulkIndexerResult)
+	}
+
+	go d.run()
+	return d
+}
+
+// deliver queues the result for delivery according to the delivery mode.
+func (d *bulkDelivery) deliver(result *BulkIndexerResult) {
+	if d.mode != BulkDeliveryDrop {
+		d.results <- result
+		return
+	}
+
+	select {
+	case d.results <- result:
+	default:
+		d.stats.numDropped.Add(1)
+		log.Trace("[opensearch] dropped bulk index result",
+			log.String("index", result.Document.Index()),
+			log.String("id", result.Document.ID()))
+	}
+}
+
+// close stops accepting results and waits until the queued results are delivered, after which the consumer channel is
+// closed. It must only be called once all workers have completed.
+func (d *bulkDelivery) close() {
+	close(d.results)
+	<-d.done
+}
+
+func (d *bulkDelivery) run() {
+	defer close(d.done)
+
+	if d.consumer != nil {
+		defer close(d.consumer)
+	}
+
+	for result := range d.results {
+		if d.handler != nil {
+			d.handler(result)
+		}
+
+		if d.consumer != nil {
+			d.consumer <- result
+		}
+	}
+}
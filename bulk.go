@@ -1,20 +1,20 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
-	"strings"
+	"sync"
 	"time"
 
-	"github.com/transientvariable/anchor"
 	"github.com/transientvariable/log-go"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/opensearch-project/opensearch-go"
-	"github.com/opensearch-project/opensearch-go/opensearchutil"
-
-	json "github.com/json-iterator/go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
 )
 
 const (
@@ -23,190 +23,555 @@ const (
 	DefaultStatsInterval = 5 * time.Second // 5s
 )
 
-// BulkIndexerResult is a container for the result of a BulkIndexer operation.
+// BulkIndexerResult is a container for the result of a BulkIndexer operation, where Document is the original Document
+// submitted with the BulkOperation. Response is nil if the operation failed before a response was received for it, e.g.
+// when the bulk request itself failed.
 type BulkIndexerResult struct {
-	Document *Document
-	Error    error
+	Document  *Document
+	Operation *BulkOperation
+	Response  *BulkResponseItem
+	Error     error
 }
 
 // BulkIndexer is a parallel and asynchronous indexer for OpenSearch.
+//
+// Operations added to the BulkIndexer are distributed across workers, where each worker buffers operations as NDJSON
+// and issues a `_bulk` request when the buffer reaches the flush size, when the flush interval elapses, or when the
+// BulkIndexer is closed. Items that fail with a retryable status or error type (e.g. 429 or 503) are retried by the
+// worker using exponential backoff, and are only reported as failed once the maximum number of attempts is reached.
+// Failed items are written to the DeadLetterSink set using WithBulkIndexerDeadLetterSink, if any. In adaptive mode,
+// enabled using WithBulkIndexerAdaptive, the flush size and the number of workers that concurrently issue requests are
+// adjusted based on cluster feedback.
 type BulkIndexer struct {
-	bulkIndexer opensearchutil.BulkIndexer
-	client      *opensearch.Client
-	consumer    chan<- *BulkIndexerResult
-	statsCtx    context.Context
-	statsCancel context.CancelFunc
+	adaptive         *bulkAdaptive
+	client           *opensearch.Client
+	closed           bool
+	deadLetter       DeadLetterSink
+	delivery         *bulkDelivery
+	flushInterval    time.Duration
+	flushSize        int
+	index            string
+	mutex            sync.RWMutex
+	name             string
+	onError          func(context.Context, error)
+	onFlushEnd       func(context.Context)
+	onFlushStart     func(context.Context) context.Context
+	pipeline         string
+	queue            chan *BulkOperation
+	refresh          string
+	retryBackoff     func() backoff.BackOff
+	retryErrorTypes  map[string]bool
+	retryMaxAttempts int
+	retryStatus      map[int]bool
+	routing          string
+	stats            *bulkIndexerStats
+	statsCancel      context.CancelFunc
+	statsDone        chan struct{}
+	timeout          time.Duration
+	waitForActive    string
+	wg               sync.WaitGroup
+	workers          int
 }
 
-// NewBulkIndexer creates a new BulkIndexer with the provided options.
+// NewBulkIndexer creates a new BulkIndexer with the provided options. An error wrapping ErrInvalid is returned if any
+// of the options is out of range.
 func NewBulkIndexer(options ...func(*BulkIndexerOptions)) (*BulkIndexer, error) {
 	opts := &BulkIndexerOptions{}
 	for _, opt := range options {
 		opt(opts)
 	}
 
-	client := NewClient()
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 
-	bulkConfig := opensearchutil.BulkIndexerConfig{
-		Client:        client,
-		FlushInterval: durationValue(opts.flushInterval, DefaultFlushInterval),
-		FlushBytes:    intValue(opts.flushSize, DefaultFlushSize),
-		NumWorkers:    intValue(opts.workers, runtime.NumCPU()),
-		Refresh:       "true",
-		OnError: func(ctx context.Context, err error) {
-			log.Error("[opensearch] bulk error", log.Err(err))
+	workers := intValue(opts.workers, runtime.NumCPU())
+	flushSize := intValue(opts.flushSize, DefaultFlushSize)
+
+	var adaptive *bulkAdaptive
+	if opts.adaptive {
+		var err error
+		if adaptive, err = newBulkAdaptive(opts, flushSize, workers); err != nil {
+			return nil, err
+		}
+		workers = max(workers, adaptive.maxWorkers)
+	}
+
+	bulkIndexer := &BulkIndexer{
+		adaptive:      adaptive,
+		client:        NewClient(),
+		deadLetter:    opts.deadLetter,
+		flushInterval: durationValue(opts.flushInterval, DefaultFlushInterval),
+		flushSize:     flushSize,
+		index:         opts.index,
+		name:          opts.name,
+		onError:       opts.onError,
+		onFlushEnd:    opts.onFlushEnd,
+		onFlushStart:  opts.onFlushStart,
+		pipeline:      opts.pipeline,
+		queue:         make(chan *BulkOperation, workers),
+		refresh:       opts.refresh,
+		retryBackoff: func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
 		},
+		retryErrorTypes:  make(map[string]bool),
+		retryMaxAttempts: intValue(opts.retryMaxAttempts, DefaultBulkRetryMaxAttempts),
+		retryStatus:      make(map[int]bool),
+		stats:            newBulkIndexerStats(),
+		routing:          opts.routing,
+		timeout:          opts.timeout,
+		waitForActive:    opts.waitForActiveShards,
+		workers:          workers,
 	}
 
-	log.Debug(fmt.Sprintf("[opensearch] creating bulk indexer with configuration:\n%s", configToJSON(bulkConfig)))
+	bulkIndexer.delivery = newBulkDelivery(opts, bulkIndexer.stats)
 
-	osBulkIndexer, err := opensearchutil.NewBulkIndexer(bulkConfig)
-	if err != nil {
-		return nil, err
+	if opts.retryBackoff != nil {
+		bulkIndexer.retryBackoff = opts.retryBackoff
 	}
 
-	bulkIndexer := &BulkIndexer{
-		bulkIndexer: osBulkIndexer,
-		client:      client,
-		consumer:    opts.consumer,
+	retryErrorTypes := DefaultBulkRetryErrorTypes
+	if opts.retryErrorTypes != nil {
+		retryErrorTypes = opts.retryErrorTypes
 	}
 
-	if opts.statsEnable {
-		bulkIndexer.statsCtx, bulkIndexer.statsCancel = context.WithCancel(context.Background())
-		go func() {
-			bulkIndexer.logStats()
-		}()
+	for _, t := range retryErrorTypes {
+		bulkIndexer.retryErrorTypes[t] = true
+	}
+
+	retryStatus := DefaultBulkRetryStatus
+	if opts.retryStatus != nil {
+		retryStatus = opts.retryStatus
+	}
+
+	for _, status := range retryStatus {
+		bulkIndexer.retryStatus[status] = true
+	}
+
+	log.Debug(fmt.Sprintf("[opensearch] creating bulk indexer with options:\n%s", opts),
+		log.String("name", bulkIndexer.name),
+		log.String("flush_interval", bulkIndexer.flushInterval.String()),
+		log.Int("flush_size", bulkIndexer.flushSize),
+		log.Int("retry_max_attempts", bulkIndexer.retryMaxAttempts),
+		log.Int("workers", workers))
+
+	for i := 0; i < workers; i++ {
+		bulkIndexer.wg.Add(1)
+		go bulkIndexer.work()
+	}
+
+	if opts.statsEnable || opts.statsHandler != nil {
+		var ctx context.Context
+		ctx, bulkIndexer.statsCancel = context.WithCancel(context.Background())
+		bulkIndexer.statsDone = make(chan struct{})
+		go bulkIndexer.reportStats(ctx,
+			durationValue(opts.statsInterval, DefaultStatsInterval),
+			opts.statsEnable,
+			opts.statsHandler)
 	}
 	return bulkIndexer, nil
 }
 
-// Add adds the provided document(s) to the BulkIndexer.
-func (b *BulkIndexer) Add(ctx context.Context, action string, document *Document) error {
-	index := strings.TrimSpace(document.Index())
-	if len(index) == 0 {
-		return ErrMalformedIndex
+// Add adds the provided operation to the BulkIndexer. The operation is validated before it is added, so malformed
+// operations (e.g. an update without a document ID) are rejected with an error wrapping ErrInvalid instead of failing
+// at the server.
+func (b *BulkIndexer) Add(ctx context.Context, operation *BulkOperation) error {
+	if operation == nil {
+		return ErrInvalid
+	}
+
+	if err := operation.validate(b.index); err != nil {
+		return err
 	}
 
 	log.Trace("[opensearch] adding bulk index item",
-		log.String("index", index),
-		log.String("id", document.ID()),
-		log.String("action", action))
+		log.String("index", operation.Document().Index()),
+		log.String("id", operation.Document().ID()),
+		log.String("action", operation.Action()))
 
-	if err := b.bulkIndexer.Add(ctx, b.prepareBulkIndexItem(action, document)); err != nil {
-		return err
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	select {
+	case b.queue <- operation:
+		b.stats.numAdded.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return nil
 }
 
-// Close ...
+// Close flushes the operations buffered by the BulkIndexer and waits for the workers to complete and all results to be
+// delivered, or until the provided context is done. The consumer channel set using WithBulkIndexerConsumer is closed
+// once all results are delivered, so it is closed when Close returns without an error; otherwise delivery continues in
+// the background and the channel is closed when it completes.
 func (b *BulkIndexer) Close(ctx context.Context) error {
-	if b.bulkIndexer != nil {
-		return b.bulkIndexer.Close(ctx)
-	}
-	return nil
-}
-
-// TODO: Consider using sync.Pool for creating bulk items
-func (b *BulkIndexer) prepareBulkIndexItem(action string, item *Document) opensearchutil.BulkIndexerItem {
-	bulkItem := opensearchutil.BulkIndexerItem{
-		Index:  item.Index(),
-		Action: action,
-		OnSuccess: func(ctx context.Context, item opensearchutil.BulkIndexerItem, response opensearchutil.BulkIndexerResponseItem) {
-			log.Trace("[opensearch] completed bulk index request",
-				log.String("index", response.Index),
-				log.String("id", response.DocumentID))
-
-			if b.consumer != nil {
-				b.consumer <- &BulkIndexerResult{
-					Document: NewDocument(
-						WithDocumentID(response.DocumentID),
-						WithIndex(response.Index),
-					)}
-			}
-		},
-		OnFailure: func(ctx context.Context, item opensearchutil.BulkIndexerItem, response opensearchutil.BulkIndexerResponseItem, err error) {
-			log.Error("[opensearch] could not complete bulk index request",
-				log.String("index", response.Index),
-				log.String("id", response.DocumentID),
-				log.String("type", response.Error.Type),
-				log.String("reason", response.Error.Reason))
-
-			if b.consumer != nil {
-				b.consumer <- &BulkIndexerResult{
-					Document: NewDocument(
-						WithDocumentID(response.DocumentID),
-						WithIndex(response.Index),
-					),
-					Error: fmt.Errorf("%s", response.Error.Reason),
-				}
-			}
-		},
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return ErrClosed
 	}
+	b.closed = true
+	close(b.queue)
+	b.mutex.Unlock()
 
-	if item.ID() != "" {
-		bulkItem.DocumentID = item.ID()
-	}
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		if b.delivery != nil {
+			b.delivery.close()
+		}
 
-	if action != "delete" && len(item.Content()) > 0 {
-		bulkItem.Body = item.Reader()
+		if b.statsCancel != nil {
+			b.statsCancel()
+			<-b.statsDone
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return bulkItem
 }
 
-func (b *BulkIndexer) logStats() {
-	ticker := time.NewTicker(DefaultStatsInterval)
+func (b *BulkIndexer) work() {
+	defer b.wg.Done()
+
+	var (
+		buf   bytes.Buffer
+		batch []*BulkOperation
+	)
+
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) > 0 {
+			ctx := context.Background()
+			if b.onFlushStart != nil {
+				ctx = b.onFlushStart(ctx)
+			}
+
+			start := time.Now()
+			b.flush(ctx, &buf, batch)
+			b.stats.flushLatency.observe(time.Since(start))
+
+			if b.onFlushEnd != nil {
+				b.onFlushEnd(ctx)
+			}
+		}
+		buf.Reset()
+		batch = nil
+	}
+
 	for {
 		select {
+		case op, ok := <-b.queue:
+			if !ok {
+				flush()
+				return
+			}
+
+			n := buf.Len()
+			if err := op.encode(&buf); err != nil {
+				buf.Truncate(n)
+				b.onFailure(op, nil, fmt.Errorf("opensearch: could not encode bulk %s: %w", op.Action(), err), 0)
+				continue
+			}
+			batch = append(batch, op)
+
+			if buf.Len() >= b.currentFlushSize() {
+				flush()
+			}
 		case <-ticker.C:
-			if b.bulkIndexer != nil {
-				log.Info(fmt.Sprintf("[opensearch] bulk stats:\n%s", anchor.ToJSONFormatted(b.bulkIndexer.Stats())))
+			flush()
+		}
+	}
+}
+
+// bulkFailure is a failed bulk item that may be retried.
+type bulkFailure struct {
+	op       *BulkOperation
+	response *BulkResponseItem
+	err      error
+}
+
+// flush issues the bulk request for the batch, retrying the items that fail with a retryable status or error type
+// until they succeed, the maximum number of attempts is reached, the backoff policy stops, or the context is done.
+func (b *BulkIndexer) flush(ctx context.Context, body *bytes.Buffer, batch []*BulkOperation) {
+	var bo backoff.BackOff
+	for attempt := 1; ; attempt++ {
+		failures := b.execute(ctx, body, batch, attempt)
+		if len(failures) == 0 {
+			return
+		}
+
+		wait := backoff.Stop
+		if attempt < b.retryMaxAttempts {
+			if bo == nil {
+				bo = b.retryBackoff()
+			}
+			wait = bo.NextBackOff()
+		}
+
+		if wait == backoff.Stop {
+			for _, f := range failures {
+				b.onFailure(f.op, f.response, f.err, attempt)
+			}
+			return
+		}
+
+		log.Warn("[opensearch] retrying bulk index items",
+			log.Int("items", len(failures)),
+			log.Int("attempt", attempt),
+			log.String("backoff", wait.String()))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			for _, f := range failures {
+				b.onFailure(f.op, f.response, errors.Join(f.err, ctx.Err()), attempt)
 			}
-		case <-b.statsCtx.Done():
 			return
 		}
+
+		body.Reset()
+		batch = make([]*BulkOperation, 0, len(failures))
+		for _, f := range failures {
+			if err := f.op.encode(body); err != nil {
+				b.onFailure(f.op, f.response, fmt.Errorf("opensearch: could not encode bulk %s: %w", f.op.Action(), err), attempt)
+				continue
+			}
+			batch = append(batch, f.op)
+		}
+		b.stats.numRetried.Add(uint64(len(batch)))
+	}
+}
+
+// execute issues the bulk request for the batch and reports the items that succeeded or failed with an error that is
+// not retryable. The items that failed with a retryable error are returned.
+func (b *BulkIndexer) execute(ctx context.Context, body *bytes.Buffer, batch []*BulkOperation, attempt int) []*bulkFailure {
+	if len(batch) == 0 {
+		return nil
+	}
+	b.stats.numRequests.Add(1)
+	b.stats.numBytes.Add(uint64(body.Len()))
+
+	log.Trace("[opensearch] flushing bulk index items",
+		log.Int("operations", len(batch)),
+		log.Int("bytes", body.Len()),
+		log.Int("attempt", attempt))
+
+	// in adaptive mode, the number of concurrent requests is limited rather than the number of concurrent flushes so
+	// that workers waiting to retry do not hold a slot
+	if b.adaptive != nil {
+		b.adaptive.limiter.acquire()
+	}
+
+	var e struct {
+		Items []map[string]*BulkResponseItem `json:"items"`
+	}
+	start := time.Now()
+	err := do(ctx, b.client, "bulk", opensearchapi.BulkRequest{
+		Index:               b.index,
+		Body:                bytes.NewReader(body.Bytes()),
+		Pipeline:            b.pipeline,
+		Refresh:             b.refresh,
+		Routing:             b.routing,
+		Timeout:             b.timeout,
+		WaitForActiveShards: b.waitForActive,
+	}, &e)
+	latency := time.Since(start)
+	b.stats.requestLatency.observe(latency)
+
+	if b.adaptive != nil {
+		b.adaptive.limiter.release()
+	}
+
+	if err == nil && len(e.Items) != len(batch) {
+		err = fmt.Errorf("opensearch: bulk response contains %d items for %d operations", len(e.Items), len(batch))
+	}
+
+	var failures []*bulkFailure
+	if err != nil {
+		log.Error("[opensearch] bulk error", log.String("name", b.name), log.Err(err))
+
+		if b.onError != nil {
+			b.onError(ctx, err)
+		}
+
+		var re *ResponseError
+		isResponseErr := errors.As(err, &re)
+		if isResponseErr && rejected(re.StatusCode, re.Type) {
+			b.observe(latency, len(batch))
+		} else {
+			b.observe(latency, 0)
+		}
+
+		retryable := isResponseErr && b.retryable(re.StatusCode, re.Type)
+		for _, op := range batch {
+			if retryable {
+				failures = append(failures, &bulkFailure{op: op, err: err})
+				continue
+			}
+			b.onFailure(op, nil, err, attempt)
+		}
+		return failures
+	}
+
+	var numRejected int
+	for i, op := range batch {
+		for action, response := range e.Items[i] {
+			response.Action = action
+			if err := response.err(); err != nil {
+				var errorType string
+				if response.Error != nil {
+					errorType = response.Error.Type
+				}
+
+				if rejected(response.Status, errorType) {
+					numRejected++
+				}
+
+				if b.retryable(response.Status, errorType) {
+					failures = append(failures, &bulkFailure{op: op, response: response, err: err})
+					continue
+				}
+				b.onFailure(op, response, err, attempt)
+				continue
+			}
+			b.onSuccess(op, response)
+		}
+	}
+	b.observe(latency, numRejected)
+	return failures
+}
+
+// observe records the latency of a bulk request and the number of its items rejected by the cluster, which adjusts the
+// flush size and concurrency in adaptive mode.
+func (b *BulkIndexer) observe(latency time.Duration, numRejected int) {
+	b.stats.numRejected.Add(uint64(numRejected))
+	if b.adaptive != nil {
+		b.adaptive.observe(latency, numRejected > 0)
+	}
+}
+
+// currentFlushSize returns the size in bytes at which workers flush their buffered operations, which is adjusted in
+// adaptive mode.
+func (b *BulkIndexer) currentFlushSize() int {
+	if b.adaptive != nil {
+		return b.adaptive.currentFlushSize()
+	}
+	return b.flushSize
+}
+
+// currentWorkers returns the number of workers that may concurrently issue bulk requests, which is adjusted in
+// adaptive mode.
+func (b *BulkIndexer) currentWorkers() int {
+	if b.adaptive != nil {
+		return b.adaptive.limiter.currentLimit()
+	}
+	return b.workers
+}
+
+// retryable returns whether a failure with the provided status code or error type is retried.
+func (b *BulkIndexer) retryable(status int, errorType string) bool {
+	return b.retryStatus[status] || (errorType != "" && b.retryErrorTypes[errorType])
+}
+
+// rejected returns whether a failure with the provided status code or error type indicates that the cluster rejected
+// the request due to load.
+func rejected(status int, errorType string) bool {
+	return status == http.StatusTooManyRequests || errorType == BulkErrorTypeRejectedExecution
+}
+
+func (b *BulkIndexer) onSuccess(op *BulkOperation, response *BulkResponseItem) {
+	b.stats.numFlushed.Add(1)
+	switch response.Action {
+	case BulkActionIndex:
+		b.stats.numIndexed.Add(1)
+	case BulkActionCreate:
+		b.stats.numCreated.Add(1)
+	case BulkActionUpdate:
+		b.stats.numUpdated.Add(1)
+	case BulkActionDelete:
+		b.stats.numDeleted.Add(1)
+	}
+
+	log.Trace("[opensearch] completed bulk index request",
+		log.String("index", response.Index),
+		log.String("id", response.ID))
+
+	if b.delivery != nil {
+		b.delivery.deliver(&BulkIndexerResult{
+			Document:  op.Document(),
+			Operation: op,
+			Response:  response,
+		})
 	}
 }
 
-func configToJSON(config opensearchutil.BulkIndexerConfig) json.RawMessage {
-	type bulkIndexConfig struct {
-		NumWorkers    int           `json:"num_workers"`    // The number of workers. Defaults to runtime.NumCPU().
-		FlushBytes    int           `json:"flush_bytes"`    // The flush threshold in bytes. Defaults to 5MB.
-		FlushInterval time.Duration `json:"flush_interval"` // The flush threshold as duration. Defaults to 30sec.
-
-		// Parameters of the Bulk Cluster.
-		Index               string
-		ErrorTrace          bool        `json:"error_trace"`
-		FilterPath          []string    `json:"filter_path,omitempty"`
-		Header              http.Header `json:"header,omitempty"`
-		Human               bool
-		Pipeline            string
-		Pretty              bool
-		Refresh             string
-		Routing             string
-		Source              []string `json:"source,omitempty"`
-		SourceExcludes      []string `json:"source_excludes,omitempty"`
-		SourceIncludes      []string `json:"source_includes,omitempty"`
-		Timeout             time.Duration
-		WaitForActiveShards string `json:"wait_for_active_shards"`
-	}
-	return anchor.ToJSONFormatted(bulkIndexConfig{
-		NumWorkers:          config.NumWorkers,
-		FlushBytes:          config.FlushBytes,
-		FlushInterval:       config.FlushInterval,
-		Index:               config.Index,
-		ErrorTrace:          config.ErrorTrace,
-		FilterPath:          config.FilterPath,
-		Header:              config.Header,
-		Human:               config.Human,
-		Pipeline:            config.Pipeline,
-		Pretty:              config.Pretty,
-		Refresh:             config.Refresh,
-		Source:              config.Source,
-		SourceExcludes:      config.SourceExcludes,
-		SourceIncludes:      config.SourceIncludes,
-		Timeout:             config.Timeout,
-		WaitForActiveShards: config.WaitForActiveShards,
-	})
+func (b *BulkIndexer) onFailure(op *BulkOperation, response *BulkResponseItem, err error, attempts int) {
+	b.stats.numFailed.Add(1)
+
+	index, id := op.Document().Index(), op.Document().ID()
+	if index == "" {
+		index = b.index
+	}
+
+	if response != nil {
+		index, id = response.Index, response.ID
+	}
+
+	log.Error("[opensearch] could not complete bulk index request",
+		log.String("name", b.name),
+		log.String("index", index),
+		log.String("id", id),
+		log.String("action", op.Action()),
+		log.Int("attempts", attempts),
+		log.Err(err))
+
+	if b.deadLetter != nil {
+		d := newDeadLetter(op, err, attempts)
+		if d.Index == "" {
+			d.Index = b.index
+		}
+
+		if d.Routing == "" {
+			d.Routing = b.routing
+		}
+
+		if d.Pipeline == "" && (op.Action() == BulkActionIndex || op.Action() == BulkActionCreate) {
+			d.Pipeline = b.pipeline
+		}
+
+		if err := b.deadLetter.Write(context.Background(), d); err != nil {
+			log.Error("[opensearch] could not write bulk item to dead letter sink",
+				log.String("index", index),
+				log.String("id", id),
+				log.Err(err))
+		} else {
+			b.stats.numDeadLettered.Add(1)
+		}
+	}
+
+	if b.delivery != nil {
+		b.delivery.deliver(&BulkIndexerResult{
+			Document:  op.Document(),
+			Operation: op,
+			Response:  response,
+			Error:     err,
+		})
+	}
 }
 
 func durationValue(value time.Duration, defaultValue time.Duration) time.Duration {
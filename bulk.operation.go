@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/transientvariable/anchor"
+
+	json "github.com/json-iterator/go"
+)
+
+// Enumeration of the actions supported by bulk operations.
+const (
+	BulkActionCreate = "create"
+	BulkActionDelete = "delete"
+	BulkActionIndex  = "index"
+	BulkActionUpdate = "update"
+)
+
+// BulkOperation is a single typed operation of a bulk request, created using BulkIndex, BulkCreate, BulkUpdate, or
+// BulkDelete.
+type BulkOperation struct {
+	action          string
+	docAsUpsert     *bool
+	document        *Document
+	ifPrimaryTerm   *int64
+	ifSeqNo         *int64
+	pipeline        string
+	requireAlias    *bool
+	retryOnConflict *int
+	routing         string
+	script          *Script
+	scriptedUpsert  *bool
+	upsert          json.RawMessage
+	version         *int64
+	versionType     string
+}
+
+// BulkIndex creates a BulkOperation that indexes the Document, replacing it if a Document with the same ID exists.
+func BulkIndex(document *Document, options ...func(*BulkOperation)) *BulkOperation {
+	return newBulkOperation(BulkActionIndex, document, options...)
+}
+
+// BulkCreate creates a BulkOperation that indexes the Document only if a Document with the same ID does not exist.
+func BulkCreate(document *Document, options ...func(*BulkOperation)) *BulkOperation {
+	return newBulkOperation(BulkActionCreate, document, options...)
+}
+
+// BulkUpdate creates a BulkOperation that updates the Document with the provided ID, where the Document content is the
+// partial document merged into the existing Document. The content may be omitted if a script is provided using
+// WithBulkScript.
+func BulkUpdate(document *Document, options ...func(*BulkOperation)) *BulkOperation {
+	return newBulkOperation(BulkActionUpdate, document, options...)
+}
+
+// BulkDelete creates a BulkOperation that deletes the Document with the provided ID.
+func BulkDelete(document *Document, options ...func(*BulkOperation)) *BulkOperation {
+	return newBulkOperation(BulkActionDelete, document, options...)
+}
+
+func newBulkOperation(action string, document *Document, options ...func(*BulkOperation)) *BulkOperation {
+	op := &BulkOperation{action: action, document: document}
+	if document != nil {
+		op.pipeline = document.Pipeline()
+		op.routing = document.Routing()
+	}
+
+	for _, opt := range options {
+		opt(op)
+	}
+	return op
+}
+
+// Action returns the action for the BulkOperation.
+func (o *BulkOperation) Action() string {
+	return o.action
+}
+
+// Document returns the Document for the BulkOperation.
+func (o *BulkOperation) Document() *Document {
+	return o.document
+}
+
+// Validate returns an error wrapping ErrInvalid if the BulkOperation is missing required fields or sets options that
+// are not supported by its action.
+func (o *BulkOperation) Validate() error {
+	return o.validate("")
+}
+
+// validate validates the BulkOperation, where defaultIndex is the index used for the operation if its Document does
+// not specify one.
+func (o *BulkOperation) validate(defaultIndex string) error {
+	if o.document == nil {
+		return fmt.Errorf("opensearch: bulk %s requires a document: %w", o.action, ErrInvalid)
+	}
+
+	if strings.TrimSpace(o.document.Index()) == "" && defaultIndex == "" {
+		return fmt.Errorf("opensearch: bulk %s: %w", o.action, ErrMalformedIndex)
+	}
+
+	switch o.action {
+	case BulkActionIndex, BulkActionCreate:
+		if len(o.document.Content()) == 0 {
+			return fmt.Errorf("opensearch: bulk %s: %w", o.action, ErrMalformedDocumentContent)
+		}
+	case BulkActionUpdate:
+		if o.document.ID() == "" {
+			return fmt.Errorf("opensearch: bulk %s requires a document id: %w", o.action, ErrInvalid)
+		}
+
+		if len(o.document.Content()) == 0 && o.script == nil {
+			return fmt.Errorf("opensearch: bulk %s requires document content or a script: %w", o.action, ErrInvalid)
+		}
+	case BulkActionDelete:
+		if o.document.ID() == "" {
+			return fmt.Errorf("opensearch: bulk %s requires a document id: %w", o.action, ErrInvalid)
+		}
+	default:
+		return fmt.Errorf("opensearch: unsupported bulk action %q: %w", o.action, ErrInvalid)
+	}
+
+	if o.action != BulkActionUpdate {
+		if o.retryOnConflict != nil || o.script != nil || len(o.upsert) > 0 || o.docAsUpsert != nil || o.scriptedUpsert != nil {
+			return fmt.Errorf("opensearch: bulk %s does not support update options: %w", o.action, ErrInvalid)
+		}
+	}
+
+	if o.pipeline != "" && (o.action == BulkActionUpdate || o.action == BulkActionDelete) {
+		return fmt.Errorf("opensearch: bulk %s does not support a pipeline: %w", o.action, ErrInvalid)
+	}
+
+	if (o.ifSeqNo == nil) != (o.ifPrimaryTerm == nil) {
+		return fmt.Errorf("opensearch: bulk %s requires both if_seq_no and if_primary_term: %w", o.action, ErrInvalid)
+	}
+	return nil
+}
+
+// String returns a string representation of the BulkOperation.
+func (o *BulkOperation) String() string {
+	om := map[string]any{
+		"action": o.action,
+		"meta":   o.metadata(),
+	}
+
+	if o.document != nil {
+		om["document"] = o.document.Content()
+	}
+	return string(anchor.ToJSONFormatted(om))
+}
+
+// encode writes the action metadata line and, if required by the action, the source line of the BulkOperation to buf
+// in NDJSON format.
+func (o *BulkOperation) encode(buf *bytes.Buffer) error {
+	meta, err := json.Marshal(map[string]any{o.action: o.metadata()})
+	if err != nil {
+		return err
+	}
+
+	source, err := o.source()
+	if err != nil {
+		return err
+	}
+
+	buf.Write(meta)
+	buf.WriteByte('\n')
+	if source == nil {
+		return nil
+	}
+
+	// Newlines can only appear as whitespace in valid JSON, so they can be replaced to keep the source on one line.
+	if bytes.ContainsAny(source, "\r\n") {
+		source = bytes.Map(func(r rune) rune {
+			if r == '\r' || r == '\n' {
+				return ' '
+			}
+			return r
+		}, source)
+	}
+	buf.Write(source)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// source returns the source line of the BulkOperation, which is the Document content for index and create operations,
+// the update body for update operations, and nil for delete operations.
+func (o *BulkOperation) source() ([]byte, error) {
+	switch o.action {
+	case BulkActionIndex, BulkActionCreate:
+		return o.document.Content(), nil
+	case BulkActionUpdate:
+		body := make(map[string]any)
+		if len(o.document.Content()) > 0 {
+			body["doc"] = o.document.Content()
+		}
+
+		if o.docAsUpsert != nil {
+			body["doc_as_upsert"] = *o.docAsUpsert
+		}
+
+		if o.script != nil {
+			body["script"] = o.script
+		}
+
+		if o.scriptedUpsert != nil {
+			body["scripted_upsert"] = *o.scriptedUpsert
+		}
+
+		if len(o.upsert) > 0 {
+			body["upsert"] = o.upsert
+		}
+		return json.Marshal(body)
+	}
+	return nil, nil
+}
+
+func (o *BulkOperation) metadata() map[string]any {
+	meta := make(map[string]any)
+	if o.document != nil {
+		if o.document.Index() != "" {
+			meta["_index"] = o.document.Index()
+		}
+
+		if o.document.ID() != "" {
+			meta["_id"] = o.document.ID()
+		}
+	}
+
+	if o.ifPrimaryTerm != nil {
+		meta["if_primary_term"] = *o.ifPrimaryTerm
+	}
+
+	if o.ifSeqNo != nil {
+		meta["if_seq_no"] = *o.ifSeqNo
+	}
+
+	if o.pipeline != "" {
+		meta["pipeline"] = o.pipeline
+	}
+
+	if o.requireAlias != nil {
+		meta["require_alias"] = *o.requireAlias
+	}
+
+	if o.retryOnConflict != nil {
+		meta["retry_on_conflict"] = *o.retryOnConflict
+	}
+
+	if o.routing != "" {
+		meta["routing"] = o.routing
+	}
+
+	if o.version != nil {
+		meta["version"] = *o.version
+	}
+
+	if o.versionType != "" {
+		meta["version_type"] = o.versionType
+	}
+	return meta
+}
+
+// WithBulkDocAsUpsert sets whether the Document content of an update operation is indexed if the Document does not
+// exist.
+func WithBulkDocAsUpsert(docAsUpsert bool) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.docAsUpsert = &docAsUpsert
+	}
+}
+
+// WithBulkIfSeqNo sets the sequence number and primary term the existing Document must have for the operation to be
+// applied, which is used for optimistic concurrency control.
+func WithBulkIfSeqNo(seqNo int64, primaryTerm int64) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.ifSeqNo = &seqNo
+		o.ifPrimaryTerm = &primaryTerm
+	}
+}
+
+// WithBulkPipeline sets the ID of the ingest pipeline used to preprocess the Document for index and create operations.
+// Defaults to Document.Pipeline().
+func WithBulkPipeline(pipeline string) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.pipeline = strings.TrimSpace(pipeline)
+	}
+}
+
+// WithBulkRequireAlias sets whether the index of the Document must be an alias.
+func WithBulkRequireAlias(requireAlias bool) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.requireAlias = &requireAlias
+	}
+}
+
+// WithBulkRetryOnConflict sets the number of times an update operation is retried when a version conflict occurs.
+func WithBulkRetryOnConflict(retries int) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		if retries >= 0 {
+			o.retryOnConflict = &retries
+		}
+	}
+}
+
+// WithBulkRouting sets the custom routing value for the operation. Defaults to Document.Routing().
+func WithBulkRouting(routing string) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.routing = strings.TrimSpace(routing)
+	}
+}
+
+// WithBulkScript sets the script executed by an update operation.
+func WithBulkScript(script Script) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.script = &script
+	}
+}
+
+// WithBulkScriptedUpsert sets whether the script of an update operation is executed if the Document does not exist.
+func WithBulkScriptedUpsert(scriptedUpsert bool) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.scriptedUpsert = &scriptedUpsert
+	}
+}
+
+// WithBulkUpsert sets the content indexed by an update operation if the Document does not exist.
+func WithBulkUpsert(upsert json.RawMessage) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.upsert = upsert
+	}
+}
+
+// WithBulkVersion sets the explicit version and version type (e.g. `external`, `external_gte`) for the operation.
+func WithBulkVersion(version int64, versionType string) func(*BulkOperation) {
+	return func(o *BulkOperation) {
+		o.version = &version
+		o.versionType = strings.TrimSpace(versionType)
+	}
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	json "github.com/json-iterator/go"
+)
+
+func TestBulkOperationValidate(t *testing.T) {
+	content := WithContent(json.RawMessage(`{"name":"test"}`))
+
+	tests := []struct {
+		name         string
+		op           *BulkOperation
+		defaultIndex string
+		err          error
+	}{
+		{
+			name: "index",
+			op:   BulkIndex(NewDocument(WithIndex("test"), content)),
+		},
+		{
+			name:         "index with default index",
+			op:           BulkIndex(NewDocument(content)),
+			defaultIndex: "test",
+		},
+		{
+			name: "index without document",
+			op:   BulkIndex(nil),
+			err:  ErrInvalid,
+		},
+		{
+			name: "index without index",
+			op:   BulkIndex(NewDocument(content)),
+			err:  ErrMalformedIndex,
+		},
+		{
+			name: "index without content",
+			op:   BulkIndex(NewDocument(WithIndex("test"))),
+			err:  ErrMalformedDocumentContent,
+		},
+		{
+			name: "index with pipeline",
+			op:   BulkIndex(NewDocument(WithIndex("test"), content), WithBulkPipeline("pipeline")),
+		},
+		{
+			name: "index with update options",
+			op:   BulkIndex(NewDocument(WithIndex("test"), content), WithBulkRetryOnConflict(3)),
+			err:  ErrInvalid,
+		},
+		{
+			name: "create without content",
+			op:   BulkCreate(NewDocument(WithIndex("test"))),
+			err:  ErrMalformedDocumentContent,
+		},
+		{
+			name: "update",
+			op:   BulkUpdate(NewDocument(WithIndex("test"), WithDocumentID("1"), content)),
+		},
+		{
+			name: "update with script",
+			op:   BulkUpdate(NewDocument(WithIndex("test"), WithDocumentID("1")), WithBulkScript(Script{Source: "ctx._source.n++"})),
+		},
+		{
+			name: "update without id",
+			op:   BulkUpdate(NewDocument(WithIndex("test"), content)),
+			err:  ErrInvalid,
+		},
+		{
+			name: "update without content or script",
+			op:   BulkUpdate(NewDocument(WithIndex("test"), WithDocumentID("1"))),
+			err:  ErrInvalid,
+		},
+		{
+			name: "update with pipeline",
+			op:   BulkUpdate(NewDocument(WithIndex("test"), WithDocumentID("1"), content), WithBulkPipeline("pipeline")),
+			err:  ErrInvalid,
+		},
+		{
+			name: "delete",
+			op:   BulkDelete(NewDocument(WithIndex("test"), WithDocumentID("1"))),
+		},
+		{
+			name: "delete without id",
+			op:   BulkDelete(NewDocument(WithIndex("test"))),
+			err:  ErrInvalid,
+		},
+		{
+			name: "delete with update options",
+			op:   BulkDelete(NewDocument(WithIndex("test"), WithDocumentID("1")), WithBulkDocAsUpsert(true)),
+			err:  ErrInvalid,
+		},
+		{
+			name: "unsupported action",
+			op:   newBulkOperation("upsert", NewDocument(WithIndex("test"), content)),
+			err:  ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.op.validate(tt.defaultIndex)
+			if tt.err == nil && err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("expected error wrapping %v, got: %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestBulkOperationEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    *BulkOperation
+		lines []string
+	}{
+		{
+			name: "index",
+			op: BulkIndex(NewDocument(
+				WithIndex("test"),
+				WithDocumentID("1"),
+				WithDocumentRouting("shard"),
+				WithContent(json.RawMessage(`{"name":"test"}`)))),
+			lines: []string{
+				`{"index":{"_index":"test","_id":"1","routing":"shard"}}`,
+				`{"name":"test"}`,
+			},
+		},
+		{
+			name: "create with multiline content",
+			op: BulkCreate(NewDocument(
+				WithIndex("test"),
+				WithContent(json.RawMessage("{\n  \"name\": \"test\"\r\n}"))),
+				WithBulkPipeline("pipeline")),
+			lines: []string{
+				`{"create":{"_index":"test","pipeline":"pipeline"}}`,
+				`{"name":"test"}`,
+			},
+		},
+		{
+			name: "update",
+			op: BulkUpdate(NewDocument(
+				WithIndex("test"),
+				WithDocumentID("1"),
+				WithContent(json.RawMessage(`{"name":"test"}`))),
+				WithBulkDocAsUpsert(true),
+				WithBulkRetryOnConflict(3)),
+			lines: []string{
+				`{"update":{"_index":"test","_id":"1","retry_on_conflict":3}}`,
+				`{"doc":{"name":"test"},"doc_as_upsert":true}`,
+			},
+		},
+		{
+			name: "update with script",
+			op: BulkUpdate(NewDocument(WithIndex("test"), WithDocumentID("1")),
+				WithBulkScript(Script{Source: "ctx._source.n += params.n", Params: map[string]any{"n": 1}}),
+				WithBulkScriptedUpsert(true),
+				WithBulkUpsert(json.RawMessage(`{"n":0}`))),
+			lines: []string{
+				`{"update":{"_index":"test","_id":"1"}}`,
+				`{"script":{"source":"ctx._source.n += params.n","params":{"n":1}},"scripted_upsert":true,"upsert":{"n":0}}`,
+			},
+		},
+		{
+			name: "delete",
+			op: BulkDelete(NewDocument(WithIndex("test"), WithDocumentID("1")),
+				WithBulkIfSeqNo(5, 2),
+				WithBulkVersion(7, "external")),
+			lines: []string{
+				`{"delete":{"_index":"test","_id":"1","if_seq_no":5,"if_primary_term":2,"version":7,"version_type":"external"}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.op.encode(&buf); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(lines) != len(tt.lines) {
+				t.Fatalf("expected %d lines, got %d:\n%s", len(tt.lines), len(lines), buf.String())
+			}
+
+			for i, line := range lines {
+				var actual, expected any
+				if err := json.Unmarshal([]byte(line), &actual); err != nil {
+					t.Fatalf("could not decode line %d %q: %v", i, line, err)
+				}
+
+				if err := json.Unmarshal([]byte(tt.lines[i]), &expected); err != nil {
+					t.Fatalf("could not decode expected line %d: %v", i, err)
+				}
+
+				if !reflect.DeepEqual(actual, expected) {
+					t.Errorf("line %d: expected %s, got %s", i, tt.lines[i], line)
+				}
+			}
+		})
+	}
+}
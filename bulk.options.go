@@ -1,45 +1,440 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/transientvariable/anchor"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+const (
+	// BulkErrorTypeRejectedExecution is the error type of bulk items and requests rejected by the cluster due to load.
+	BulkErrorTypeRejectedExecution = "es_rejected_execution_exception"
+
+	// DefaultBulkRetryMaxAttempts is the default maximum number of attempts for a failed bulk item, including the first
+	// attempt.
+	DefaultBulkRetryMaxAttempts = 3
+)
+
+var (
+	// DefaultBulkRetryStatus are the status codes of failed bulk items that are retried by default.
+	DefaultBulkRetryStatus = []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+
+	// DefaultBulkRetryErrorTypes are the error types of failed bulk items that are retried by default.
+	DefaultBulkRetryErrorTypes = []string{BulkErrorTypeRejectedExecution}
 )
 
 // BulkIndexerOptions is a container for options used for configuring the BulkIndexer.
 type BulkIndexerOptions struct {
-	consumer      chan<- *BulkIndexerResult
-	flushInterval time.Duration
-	flushSize     int
-	name          string
-	statsEnable   bool
-	workers       int
+	adaptive              bool
+	adaptiveMaxFlushSize  int
+	adaptiveMaxWorkers    int
+	adaptiveMinFlushSize  int
+	adaptiveMinWorkers    int
+	adaptiveTargetLatency time.Duration
+	consumer              chan<- *BulkIndexerResult
+	deadLetter            DeadLetterSink
+	deliveryBufferSize    int
+	deliveryMode          string
+	flushInterval         time.Duration
+	flushSize             int
+	index                 string
+	name                  string
+	onError               func(context.Context, error)
+	onFlushEnd            func(context.Context)
+	onFlushStart          func(context.Context) context.Context
+	pipeline              string
+	refresh               string
+	resultHandler         func(*BulkIndexerResult)
+	retryBackoff          func() backoff.BackOff
+	retryErrorTypes       []string
+	retryMaxAttempts      int
+	retryStatus           []int
+	routing               string
+	statsEnable           bool
+	statsHandler          func(BulkIndexerStats)
+	statsInterval         time.Duration
+	timeout               time.Duration
+	waitForActiveShards   string
+	workers               int
 }
 
 // String returns a string representation of BulkIndexerOptions.
 func (o *BulkIndexerOptions) String() string {
 	options := make(map[string]any)
+	options["adaptive"] = o.adaptive
+	if o.adaptive {
+		options["adaptive_flush_size"] = []int{o.adaptiveMinFlushSize, o.adaptiveMaxFlushSize}
+		options["adaptive_target_latency"] = o.adaptiveTargetLatency.String()
+		options["adaptive_workers"] = []int{o.adaptiveMinWorkers, o.adaptiveMaxWorkers}
+	}
+	options["dead_letter"] = o.deadLetter != nil
+	options["delivery_buffer_size"] = o.deliveryBufferSize
+	options["delivery_mode"] = o.deliveryMode
+	options["flush_interval"] = o.flushInterval.String()
+	options["flush_size"] = o.flushSize
+	options["index"] = o.index
 	options["name"] = o.name
+	options["pipeline"] = o.pipeline
+	options["refresh"] = o.refresh
+	options["retry_error_types"] = o.retryErrorTypes
+	options["retry_max_attempts"] = o.retryMaxAttempts
+	options["retry_status"] = o.retryStatus
+	options["routing"] = o.routing
 	options["stats_enable"] = o.statsEnable
+	options["stats_interval"] = o.statsInterval.String()
+	options["timeout"] = o.timeout.String()
+	options["wait_for_active_shards"] = o.waitForActiveShards
+	options["workers"] = o.workers
 	return string(anchor.ToJSONFormatted(options))
 }
 
-// WithConsumer ...
-func WithConsumer(consumer chan<- *BulkIndexerResult) func(*BulkIndexerOptions) {
+// validate returns an error wrapping ErrInvalid if any of the options is out of range.
+func (o *BulkIndexerOptions) validate() error {
+	if o.workers < 0 {
+		return fmt.Errorf("opensearch: bulk indexer workers must not be negative, got %d: %w", o.workers, ErrInvalid)
+	}
+
+	if o.flushSize < 0 {
+		return fmt.Errorf("opensearch: bulk indexer flush size must not be negative, got %d: %w", o.flushSize, ErrInvalid)
+	}
+
+	if o.flushInterval < 0 {
+		return fmt.Errorf("opensearch: bulk indexer flush interval must not be negative, got %s: %w",
+			o.flushInterval, ErrInvalid)
+	}
+
+	if o.timeout < 0 {
+		return fmt.Errorf("opensearch: bulk indexer timeout must not be negative, got %s: %w", o.timeout, ErrInvalid)
+	}
+
+	if o.statsInterval < 0 {
+		return fmt.Errorf("opensearch: bulk indexer stats interval must not be negative, got %s: %w",
+			o.statsInterval, ErrInvalid)
+	}
+
+	if o.adaptiveMinFlushSize < 0 || o.adaptiveMaxFlushSize < 0 {
+		return fmt.Errorf("opensearch: bulk indexer adaptive flush size bounds must not be negative, got [%d, %d]: %w",
+			o.adaptiveMinFlushSize, o.adaptiveMaxFlushSize, ErrInvalid)
+	}
+
+	if o.adaptiveMinWorkers < 0 || o.adaptiveMaxWorkers < 0 {
+		return fmt.Errorf("opensearch: bulk indexer adaptive worker bounds must not be negative, got [%d, %d]: %w",
+			o.adaptiveMinWorkers, o.adaptiveMaxWorkers, ErrInvalid)
+	}
+
+	if o.adaptiveTargetLatency < 0 {
+		return fmt.Errorf("opensearch: bulk indexer adaptive target latency must not be negative, got %s: %w",
+			o.adaptiveTargetLatency, ErrInvalid)
+	}
+
+	if o.retryMaxAttempts < 0 {
+		return fmt.Errorf("opensearch: bulk indexer retry max attempts must not be negative, got %d: %w",
+			o.retryMaxAttempts, ErrInvalid)
+	}
+
+	if o.deliveryBufferSize < 0 {
+		return fmt.Errorf("opensearch: bulk indexer delivery buffer size must not be negative, got %d: %w",
+			o.deliveryBufferSize, ErrInvalid)
+	}
+
+	switch o.deliveryMode {
+	case "", BulkDeliveryBlock, BulkDeliveryBuffer, BulkDeliveryDrop:
+	default:
+		return fmt.Errorf("opensearch: unsupported bulk indexer delivery mode %q: %w", o.deliveryMode, ErrInvalid)
+	}
+
+	switch o.refresh {
+	case "", "true", "false", "wait_for":
+	default:
+		return fmt.Errorf("opensearch: bulk indexer refresh must be `true`, `false`, or `wait_for`, got %q: %w",
+			o.refresh, ErrInvalid)
+	}
+
+	if o.waitForActiveShards != "" && o.waitForActiveShards != "all" {
+		if n, err := strconv.Atoi(o.waitForActiveShards); err != nil || n < 0 {
+			return fmt.Errorf("opensearch: bulk indexer wait_for_active_shards must be `all` or a non-negative integer, got %q: %w",
+				o.waitForActiveShards, ErrInvalid)
+		}
+	}
+	return nil
+}
+
+// WithBulkIndexerAdaptive sets whether the BulkIndexer adjusts its flush size and concurrency based on the latency of
+// its bulk requests and the rate at which items are rejected by the cluster (e.g. status 429), using additive increase
+// and multiplicative decrease (AIMD) within the bounds set using WithBulkIndexerAdaptiveFlushSize and
+// WithBulkIndexerAdaptiveWorkers. The flush size set using WithBulkIndexerFlushSize and the number of workers set using
+// WithBulkIndexerWorkers are used as the starting values.
+func WithBulkIndexerAdaptive(enable bool) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.adaptive = enable
+	}
+}
+
+// WithBulkIndexerAdaptiveFlushSize sets the bounds in bytes of the flush size in adaptive mode. Defaults to
+// DefaultBulkAdaptiveMinFlushSize and DefaultBulkAdaptiveMaxFlushSize.
+func WithBulkIndexerAdaptiveFlushSize(minSize int, maxSize int) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.adaptiveMinFlushSize = minSize
+		options.adaptiveMaxFlushSize = maxSize
+	}
+}
+
+// WithBulkIndexerAdaptiveTargetLatency sets the latency above which a bulk request is considered to overload the
+// cluster in adaptive mode. Defaults to DefaultBulkAdaptiveTargetLatency.
+func WithBulkIndexerAdaptiveTargetLatency(latency time.Duration) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.adaptiveTargetLatency = latency
+	}
+}
+
+// WithBulkIndexerAdaptiveWorkers sets the bounds of the number of workers that concurrently issue bulk requests in
+// adaptive mode. Defaults to DefaultBulkAdaptiveMinWorkers and DefaultBulkAdaptiveMaxWorkers, or the number of workers
+// set using WithBulkIndexerWorkers if greater.
+func WithBulkIndexerAdaptiveWorkers(minWorkers int, maxWorkers int) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.adaptiveMinWorkers = minWorkers
+		options.adaptiveMaxWorkers = maxWorkers
+	}
+}
+
+// WithBulkIndexerConsumer sets the channel the results of the BulkIndexer are delivered to according to the delivery
+// mode set using WithBulkIndexerDeliveryMode. The channel is closed by the BulkIndexer once all results are delivered,
+// which happens before BulkIndexer.Close returns.
+func WithBulkIndexerConsumer(consumer chan<- *BulkIndexerResult) func(*BulkIndexerOptions) {
 	return func(options *BulkIndexerOptions) {
 		options.consumer = consumer
 	}
 }
 
-// WithName ...
-func WithName(name string) func(*BulkIndexerOptions) {
+// WithBulkIndexerDeadLetterSink sets the DeadLetterSink that receives bulk items that permanently failed, i.e. items
+// that failed with an error that is not retryable or exhausted their retry attempts. The sink is not closed by the
+// BulkIndexer.
+func WithBulkIndexerDeadLetterSink(sink DeadLetterSink) func(*BulkIndexerOptions) {
 	return func(options *BulkIndexerOptions) {
-		options.name = name
+		options.deadLetter = sink
 	}
 }
 
-func WithStatsEnable(enable bool) func(*BulkIndexerOptions) {
+// WithBulkIndexerDeliveryBufferSize sets the size of the buffer used for delivering results when the delivery mode is
+// BulkDeliveryBuffer or BulkDeliveryDrop. Defaults to DefaultBulkDeliveryBufferSize.
+func WithBulkIndexerDeliveryBufferSize(size int) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.deliveryBufferSize = size
+	}
+}
+
+// WithBulkIndexerDeliveryMode sets the mode used for delivering results to the consumer channel and result handler,
+// which is one of BulkDeliveryBlock, BulkDeliveryBuffer, or BulkDeliveryDrop. Defaults to BulkDeliveryBlock.
+func WithBulkIndexerDeliveryMode(mode string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.deliveryMode = strings.TrimSpace(mode)
+	}
+}
+
+// WithBulkIndexerFlushInterval sets the interval after which each worker of the BulkIndexer flushes its buffered
+// operations. Defaults to DefaultFlushInterval.
+func WithBulkIndexerFlushInterval(interval time.Duration) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.flushInterval = interval
+	}
+}
+
+// WithBulkIndexerFlushSize sets the size in bytes of the NDJSON body at which each worker of the BulkIndexer flushes
+// its buffered operations. Defaults to DefaultFlushSize.
+func WithBulkIndexerFlushSize(size int) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.flushSize = size
+	}
+}
+
+// WithBulkIndexerIndex sets the default index for operations added to the BulkIndexer, which is used for operations
+// whose Document does not specify an index.
+func WithBulkIndexerIndex(index string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.index = strings.TrimSpace(index)
+	}
+}
+
+// WithBulkIndexerRouting sets the default routing value for operations added to the BulkIndexer, which is overridden by
+// the routing of an individual BulkOperation.
+func WithBulkIndexerRouting(routing string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.routing = strings.TrimSpace(routing)
+	}
+}
+
+// WithBulkIndexerTimeout sets the period each bulk request issued by the BulkIndexer waits for shard availability,
+// index creation, and mapping updates.
+func WithBulkIndexerTimeout(timeout time.Duration) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.timeout = timeout
+	}
+}
+
+// WithBulkIndexerName sets the name of the BulkIndexer, which is included in its log messages.
+func WithBulkIndexerName(name string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.name = strings.TrimSpace(name)
+	}
+}
+
+// WithBulkIndexerOnError sets the function called when a bulk request issued by the BulkIndexer fails, as opposed to
+// the failure of an individual item, which is reported by BulkIndexerResult.Error.
+func WithBulkIndexerOnError(fn func(context.Context, error)) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.onError = fn
+	}
+}
+
+// WithBulkIndexerOnFlushEnd sets the function called when a worker of the BulkIndexer completes a flush, including
+// retries, with the context returned by the function set using WithBulkIndexerOnFlushStart.
+func WithBulkIndexerOnFlushEnd(fn func(context.Context)) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.onFlushEnd = fn
+	}
+}
+
+// WithBulkIndexerOnFlushStart sets the function called when a worker of the BulkIndexer starts a flush. The returned
+// context is used for the bulk requests of the flush, e.g. for tracing.
+func WithBulkIndexerOnFlushStart(fn func(context.Context) context.Context) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.onFlushStart = fn
+	}
+}
+
+// WithBulkIndexerPipeline sets the ID of the default ingest pipeline used to preprocess documents added to the
+// BulkIndexer, which is overridden by the pipeline of an individual BulkOperation.
+func WithBulkIndexerPipeline(pipeline string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.pipeline = strings.TrimSpace(pipeline)
+	}
+}
+
+// WithBulkIndexerRefresh sets whether the bulk requests issued by the BulkIndexer refresh the affected shards to make
+// the operations visible to search, which is one of `true`, `false`, or `wait_for`. Defaults to no refresh, in which
+// case the operations become visible after the next periodic refresh of the index.
+func WithBulkIndexerRefresh(refresh string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.refresh = strings.TrimSpace(refresh)
+	}
+}
+
+// WithBulkIndexerResultHandler sets the function called with each result of the BulkIndexer, as an alternative or in
+// addition to the consumer channel set using WithBulkIndexerConsumer. Results are delivered to the handler from a
+// single goroutine according to the delivery mode set using WithBulkIndexerDeliveryMode.
+func WithBulkIndexerResultHandler(handler func(*BulkIndexerResult)) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.resultHandler = handler
+	}
+}
+
+// WithBulkIndexerRetryBackoff sets the function that creates the backoff policy used between attempts of failed bulk
+// items, which is called for each batch of retried items. Defaults to backoff.NewExponentialBackOff.
+func WithBulkIndexerRetryBackoff(fn func() backoff.BackOff) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.retryBackoff = fn
+	}
+}
+
+// WithBulkIndexerRetryErrorTypes sets the error types (e.g. `es_rejected_execution_exception`) of failed bulk items
+// that are retried, in addition to those with a retryable status. Defaults to DefaultBulkRetryErrorTypes.
+func WithBulkIndexerRetryErrorTypes(errorTypes ...string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.retryErrorTypes = []string{}
+		for _, t := range errorTypes {
+			if t = strings.TrimSpace(t); t != "" {
+				options.retryErrorTypes = append(options.retryErrorTypes, t)
+			}
+		}
+	}
+}
+
+// WithBulkIndexerRetryMaxAttempts sets the maximum number of attempts for a bulk item, including the first attempt,
+// where 1 disables retries. Defaults to DefaultBulkRetryMaxAttempts.
+func WithBulkIndexerRetryMaxAttempts(attempts int) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.retryMaxAttempts = attempts
+	}
+}
+
+// WithBulkIndexerRetryStatus sets the status codes of failed bulk items that are retried. Defaults to
+// DefaultBulkRetryStatus.
+func WithBulkIndexerRetryStatus(status ...int) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.retryStatus = append([]int{}, status...)
+	}
+}
+
+// WithBulkIndexerWaitForActiveShards sets the number of shard copies that must be active before the bulk requests
+// issued by the BulkIndexer proceed, which is either `all` or a non-negative integer.
+func WithBulkIndexerWaitForActiveShards(shards string) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.waitForActiveShards = strings.TrimSpace(shards)
+	}
+}
+
+// WithBulkIndexerWorkers sets the number of workers of the BulkIndexer, each of which buffers operations and issues
+// bulk requests concurrently. Defaults to runtime.NumCPU.
+func WithBulkIndexerWorkers(workers int) func(*BulkIndexerOptions) {
+	return func(options *BulkIndexerOptions) {
+		options.workers = workers
+	}
+}
+
+// WithBulkIndexerStatsEnable sets whether the BulkIndexer logs its counters at the interval set using
+// WithBulkIndexerStatsInterval.
+func WithBulkIndexerStatsEnable(enable bool) func(*BulkIndexerOptions) {
 	return func(o *BulkIndexerOptions) {
 		o.statsEnable = enable
 	}
 }
+
+// WithBulkIndexerStatsHandler sets the function called with a snapshot of the BulkIndexer counters at the interval set
+// using WithBulkIndexerStatsInterval, e.g. for exporting them to a metrics backend. The function is called a final time
+// when the BulkIndexer is closed.
+func WithBulkIndexerStatsHandler(handler func(BulkIndexerStats)) func(*BulkIndexerOptions) {
+	return func(o *BulkIndexerOptions) {
+		o.statsHandler = handler
+	}
+}
+
+// WithBulkIndexerStatsInterval sets the interval at which the BulkIndexer counters are logged and passed to the
+// function set using WithBulkIndexerStatsHandler. Defaults to DefaultStatsInterval.
+func WithBulkIndexerStatsInterval(interval time.Duration) func(*BulkIndexerOptions) {
+	return func(o *BulkIndexerOptions) {
+		o.statsInterval = interval
+	}
+}
+
+// WithConsumer sets the channel the results of the BulkIndexer are delivered to.
+//
+// Deprecated: use WithBulkIndexerConsumer.
+func WithConsumer(consumer chan<- *BulkIndexerResult) func(*BulkIndexerOptions) {
+	return WithBulkIndexerConsumer(consumer)
+}
+
+// WithName sets the name of the BulkIndexer.
+//
+// Deprecated: use WithBulkIndexerName.
+func WithName(name string) func(*BulkIndexerOptions) {
+	return WithBulkIndexerName(name)
+}
+
+// WithStatsEnable sets whether the BulkIndexer logs its counters.
+//
+// Deprecated: use WithBulkIndexerStatsEnable.
+func WithStatsEnable(enable bool) func(*BulkIndexerOptions) {
+	return WithBulkIndexerStatsEnable(enable)
+}
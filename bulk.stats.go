@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+)
+
+// DefaultBulkLatencyBuckets are the upper bounds of the buckets of the latency histograms reported by
+// BulkIndexerStats. Latencies greater than the last bound are counted by a final bucket without an upper bound.
+var DefaultBulkLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// BulkIndexerStats is a snapshot of the counters of a BulkIndexer.
+type BulkIndexerStats struct {
+	NumAdded        uint64 `json:"num_added"`
+	NumFlushed      uint64 `json:"num_flushed"`
+	NumFailed       uint64 `json:"num_failed"`
+	NumIndexed      uint64 `json:"num_indexed"`
+	NumCreated      uint64 `json:"num_created"`
+	NumUpdated      uint64 `json:"num_updated"`
+	NumDeleted      uint64 `json:"num_deleted"`
+	NumRequests     uint64 `json:"num_requests"`
+	NumRetried      uint64 `json:"num_retried"`
+	NumDeadLettered uint64 `json:"num_dead_lettered"`
+	NumDropped      uint64 `json:"num_dropped"`
+	NumRejected     uint64 `json:"num_rejected"`
+
+	// NumBytes is the total size in bytes of the NDJSON bodies of the bulk requests, including retries.
+	NumBytes uint64 `json:"num_bytes"`
+
+	// FlushSize is the current size in bytes at which workers flush their buffered operations.
+	FlushSize int `json:"flush_size"`
+
+	// Workers is the current number of workers that may concurrently issue bulk requests.
+	Workers int `json:"workers"`
+
+	// RequestLatency is the latency of each bulk request.
+	RequestLatency BulkLatencyHistogram `json:"request_latency"`
+
+	// FlushLatency is the latency of each flush, including the retries of failed items.
+	FlushLatency BulkLatencyHistogram `json:"flush_latency"`
+}
+
+// String returns a string representation of the BulkIndexerStats.
+func (s BulkIndexerStats) String() string {
+	return string(anchor.ToJSONFormatted(s))
+}
+
+// BulkLatencyHistogram is a snapshot of a latency histogram, where each bucket counts the observations less than or
+// equal to its upper bound that are greater than the upper bound of the previous bucket.
+type BulkLatencyHistogram struct {
+	Count   uint64              `json:"count"`
+	Sum     time.Duration       `json:"sum"`
+	Buckets []BulkLatencyBucket `json:"buckets"`
+}
+
+// Mean returns the mean latency, or zero if there are no observations.
+func (h BulkLatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// BulkLatencyBucket is a bucket of a BulkLatencyHistogram, where an UpperBound of zero denotes the final bucket without
+// an upper bound.
+type BulkLatencyBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      uint64        `json:"count"`
+}
+
+type bulkIndexerStats struct {
+	numAdded        atomic.Uint64
+	numFlushed      atomic.Uint64
+	numFailed       atomic.Uint64
+	numIndexed      atomic.Uint64
+	numCreated      atomic.Uint64
+	numUpdated      atomic.Uint64
+	numDeleted      atomic.Uint64
+	numRequests     atomic.Uint64
+	numRetried      atomic.Uint64
+	numDeadLettered atomic.Uint64
+	numDropped      atomic.Uint64
+	numRejected     atomic.Uint64
+	numBytes        atomic.Uint64
+	requestLatency  *latencyHistogram
+	flushLatency    *latencyHistogram
+}
+
+func newBulkIndexerStats() *bulkIndexerStats {
+	return &bulkIndexerStats{
+		requestLatency: newLatencyHistogram(DefaultBulkLatencyBuckets),
+		flushLatency:   newLatencyHistogram(DefaultBulkLatencyBuckets),
+	}
+}
+
+func (s *bulkIndexerStats) snapshot() BulkIndexerStats {
+	return BulkIndexerStats{
+		NumAdded:        s.numAdded.Load(),
+		NumFlushed:      s.numFlushed.Load(),
+		NumFailed:       s.numFailed.Load(),
+		NumIndexed:      s.numIndexed.Load(),
+		NumCreated:      s.numCreated.Load(),
+		NumUpdated:      s.numUpdated.Load(),
+		NumDeleted:      s.numDeleted.Load(),
+		NumRequests:     s.numRequests.Load(),
+		NumRetried:      s.numRetried.Load(),
+		NumDeadLettered: s.numDeadLettered.Load(),
+		NumDropped:      s.numDropped.Load(),
+		NumRejected:     s.numRejected.Load(),
+		NumBytes:        s.numBytes.Load(),
+		RequestLatency:  s.requestLatency.snapshot(),
+		FlushLatency:    s.flushLatency.snapshot(),
+	}
+}
+
+// latencyHistogram is a lock-free histogram of latencies with fixed bucket bounds.
+type latencyHistogram struct {
+	bounds []time.Duration
+	counts []atomic.Uint64
+	count  atomic.Uint64
+	sum    atomic.Int64
+}
+
+func newLatencyHistogram(bounds []time.Duration) *latencyHistogram {
+	return &latencyHistogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(h.bounds) && d > h.bounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *latencyHistogram) snapshot() BulkLatencyHistogram {
+	buckets := make([]BulkLatencyBucket, len(h.counts))
+	for i := range h.counts {
+		buckets[i].Count = h.counts[i].Load()
+		if i < len(h.bounds) {
+			buckets[i].UpperBound = h.bounds[i]
+		}
+	}
+
+	return BulkLatencyHistogram{
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+		Buckets: buckets,
+	}
+}
+
+// Stats returns a snapshot of the BulkIndexer counters.
+func (b *BulkIndexer) Stats() BulkIndexerStats {
+	stats := b.stats.snapshot()
+	stats.FlushSize = b.currentFlushSize()
+	stats.Workers = b.currentWorkers()
+	return stats
+}
+
+// reportStats reports the BulkIndexer counters at the provided interval until the context is done, after which the
+// counters are reported a final time.
+func (b *BulkIndexer) reportStats(ctx context.Context, interval time.Duration, logEnable bool, handler func(BulkIndexerStats)) {
+	defer close(b.statsDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	report := func() {
+		stats := b.Stats()
+		if logEnable {
+			log.Info(fmt.Sprintf("[opensearch] bulk stats:\n%s", stats), log.String("name", b.name))
+		}
+
+		if handler != nil {
+			handler(stats)
+		}
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			report()
+		case <-ctx.Done():
+			report()
+			return
+		}
+	}
+}
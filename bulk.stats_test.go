@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	bounds := []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		counts    []uint64
+		mean      time.Duration
+	}{
+		{
+			name:   "empty",
+			counts: []uint64{0, 0, 0, 0},
+		},
+		{
+			name:      "upper bounds are inclusive",
+			latencies: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second},
+			counts:    []uint64{1, 1, 1, 0},
+			mean:      370 * time.Millisecond,
+		},
+		{
+			name:      "between bounds",
+			latencies: []time.Duration{0, 5 * time.Millisecond, 11 * time.Millisecond, 500 * time.Millisecond},
+			counts:    []uint64{2, 1, 1, 0},
+			mean:      129 * time.Millisecond,
+		},
+		{
+			name:      "above last bound",
+			latencies: []time.Duration{time.Second + 1, 5*time.Second + 1},
+			counts:    []uint64{0, 0, 0, 2},
+			mean:      3*time.Second + 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newLatencyHistogram(bounds)
+
+			var sum time.Duration
+			for _, l := range tt.latencies {
+				h.observe(l)
+				sum += l
+			}
+
+			snapshot := h.snapshot()
+			if snapshot.Count != uint64(len(tt.latencies)) {
+				t.Fatalf("expected count %d, got %d", len(tt.latencies), snapshot.Count)
+			}
+
+			if snapshot.Sum != sum {
+				t.Fatalf("expected sum %s, got %s", sum, snapshot.Sum)
+			}
+
+			if snapshot.Mean() != tt.mean {
+				t.Fatalf("expected mean %s, got %s", tt.mean, snapshot.Mean())
+			}
+
+			expected := make([]BulkLatencyBucket, len(tt.counts))
+			for i, c := range tt.counts {
+				expected[i].Count = c
+				if i < len(bounds) {
+					expected[i].UpperBound = bounds[i]
+				}
+			}
+
+			if !reflect.DeepEqual(snapshot.Buckets, expected) {
+				t.Fatalf("expected buckets %v, got %v", expected, snapshot.Buckets)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+	"github.com/transientvariable/repository-opensearch-go/bandaid"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// DataStream represents an OpenSearch data stream.
+type DataStream struct {
+	Name           string            `json:"name"`
+	TimestampField string            `json:"timestamp_field"`
+	Indices        []DataStreamIndex `json:"indices"`
+	Generation     int               `json:"generation"`
+	Status         string            `json:"status"`
+	Template       string            `json:"template"`
+}
+
+// DataStreamIndex represents a backing index of a DataStream.
+type DataStreamIndex struct {
+	Name string `json:"index_name"`
+	UUID string `json:"index_uuid"`
+}
+
+// WriteIndex returns the name of the current write index for the DataStream, which is the most recently created
+// backing index.
+func (d *DataStream) WriteIndex() string {
+	if len(d.Indices) > 0 {
+		return d.Indices[len(d.Indices)-1].Name
+	}
+	return ""
+}
+
+// String returns a string representation of the DataStream.
+func (d *DataStream) String() string {
+	return string(anchor.ToJSONFormatted(d))
+}
+
+// DataStreamStats represents the statistics for one or more data streams.
+type DataStreamStats struct {
+	DataStreamCount     int   `json:"data_stream_count"`
+	BackingIndices      int   `json:"backing_indices"`
+	TotalStoreSizeBytes int64 `json:"total_store_size_bytes"`
+	DataStreams         []struct {
+		DataStream       string `json:"data_stream"`
+		BackingIndices   int    `json:"backing_indices"`
+		StoreSizeBytes   int64  `json:"store_size_bytes"`
+		MaximumTimestamp int64  `json:"maximum_timestamp"`
+	} `json:"data_streams"`
+}
+
+// String returns a string representation of the DataStreamStats.
+func (s *DataStreamStats) String() string {
+	return string(anchor.ToJSONFormatted(s))
+}
+
+// RolloverResult represents the result of rolling over a data stream or alias to a new write index.
+type RolloverResult struct {
+	OldIndex   string          `json:"old_index"`
+	NewIndex   string          `json:"new_index"`
+	RolledOver bool            `json:"rolled_over"`
+	DryRun     bool            `json:"dry_run"`
+	Conditions map[string]bool `json:"conditions,omitempty"`
+}
+
+// String returns a string representation of the RolloverResult.
+func (r *RolloverResult) String() string {
+	return string(anchor.ToJSONFormatted(r))
+}
+
+// DataStreamService provides operations for managing OpenSearch data streams.
+type DataStreamService struct {
+	client *opensearch.Client
+}
+
+// DataStreams returns the DataStreamService for the Repository.
+func (r *Repository) DataStreams() *DataStreamService {
+	return &DataStreamService{client: r.client}
+}
+
+// Create creates the data stream. A matching index template with data streams enabled must exist.
+func (s *DataStreamService) Create(ctx context.Context, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrMalformedIndex
+	}
+
+	log.Debug("[opensearch] creating data stream", log.String("name", name))
+
+	return do(ctx, s.client, "create data stream", bandaid.IndicesCreateDataStreamRequest{Name: name}, nil)
+}
+
+// Get returns the data streams matching the provided names, or all data streams if no names are provided. Names may
+// include wildcard (`*`) expressions.
+func (s *DataStreamService) Get(ctx context.Context, names ...string) ([]*DataStream, error) {
+	n, _ := indexNames(names...)
+
+	var e struct {
+		DataStreams []struct {
+			DataStream
+			TimestampField struct {
+				Name string `json:"name"`
+			} `json:"timestamp_field"`
+		} `json:"data_streams"`
+	}
+	if err := do(ctx, s.client, "get data stream", bandaid.IndicesGetDataStreamRequest{Name: n}, &e); err != nil {
+		return nil, err
+	}
+
+	dataStreams := make([]*DataStream, 0, len(e.DataStreams))
+	for _, v := range e.DataStreams {
+		ds := v.DataStream
+		ds.TimestampField = v.TimestampField.Name
+		dataStreams = append(dataStreams, &ds)
+	}
+	return dataStreams, nil
+}
+
+// Exists returns whether the data stream exists.
+func (s *DataStreamService) Exists(ctx context.Context, name string) (bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false, ErrMalformedIndex
+	}
+
+	ds, err := s.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(ds) > 0, nil
+}
+
+// Delete deletes the provided data streams and their backing indices.
+func (s *DataStreamService) Delete(ctx context.Context, names ...string) error {
+	n, err := indexNames(names...)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("[opensearch] deleting data streams", log.String("names", strings.Join(n, ",")))
+
+	return do(ctx, s.client, "delete data stream", bandaid.IndicesDeleteDataStreamRequest{Name: n}, nil)
+}
+
+// Stats returns the statistics for the data streams matching the provided names, or all data streams if no names are
+// provided.
+func (s *DataStreamService) Stats(ctx context.Context, names ...string) (*DataStreamStats, error) {
+	n, _ := indexNames(names...)
+
+	var stats DataStreamStats
+	if err := do(ctx, s.client, "data stream stats", bandaid.IndicesDataStreamsStatsRequest{Name: n}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Rollover creates a new write index for the data stream. If conditions (e.g. `max_age`, `max_docs`, `max_size`) are
+// provided, the rollover is only performed if at least one of them is met.
+func (s *DataStreamService) Rollover(ctx context.Context, name string, conditions map[string]any) (*RolloverResult, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrMalformedIndex
+	}
+
+	log.Debug("[opensearch] rolling over data stream", log.String("name", name))
+
+	request := opensearchapi.IndicesRolloverRequest{Alias: name}
+	if len(conditions) > 0 {
+		request.Body = bytes.NewReader(anchor.ToJSON(map[string]any{"conditions": conditions}))
+	}
+
+	var result RolloverResult
+	if err := do(ctx, s.client, "rollover data stream", request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
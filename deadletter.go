@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	json "github.com/json-iterator/go"
+)
+
+// DeadLetter is a bulk item that permanently failed, which contains everything required for replaying it using
+// Repository.ReplayDeadLetters.
+type DeadLetter struct {
+	Timestamp   time.Time       `json:"@timestamp"`
+	Action      string          `json:"action"`
+	Index       string          `json:"index"`
+	ID          string          `json:"id,omitempty"`
+	Routing     string          `json:"routing,omitempty"`
+	Pipeline    string          `json:"pipeline,omitempty"`
+	Content     json.RawMessage `json:"content,omitempty"`
+	Source      json.RawMessage `json:"source,omitempty"`
+	Status      int             `json:"status,omitempty"`
+	ErrorType   string          `json:"error_type,omitempty"`
+	ErrorReason string          `json:"error_reason,omitempty"`
+	Attempts    int             `json:"attempts"`
+}
+
+// newDeadLetter creates the DeadLetter for the BulkOperation that failed with the provided error after the provided
+// number of attempts.
+func newDeadLetter(op *BulkOperation, err error, attempts int) *DeadLetter {
+	d := &DeadLetter{
+		Timestamp: time.Now().UTC(),
+		Action:    op.Action(),
+		Index:     op.Document().Index(),
+		ID:        op.Document().ID(),
+		Routing:   op.routing,
+		Pipeline:  op.pipeline,
+		Content:   op.Document().Content(),
+		Attempts:  attempts,
+	}
+
+	if op.Action() == BulkActionUpdate {
+		if source, err := op.source(); err == nil {
+			d.Source = source
+		}
+	}
+
+	var (
+		itemErr     *BulkItemError
+		responseErr *ResponseError
+	)
+	switch {
+	case errors.As(err, &itemErr):
+		d.Status, d.ErrorType, d.ErrorReason = itemErr.Status, itemErr.Type, itemErr.Reason
+	case errors.As(err, &responseErr):
+		d.Status, d.ErrorType, d.ErrorReason = responseErr.StatusCode, responseErr.Type, responseErr.Reason
+	case err != nil:
+		d.ErrorReason = err.Error()
+	}
+	return d
+}
+
+// Operation returns the BulkOperation for replaying the DeadLetter. Optimistic concurrency control and versioning
+// options of the original operation are not preserved.
+func (d *DeadLetter) Operation() (*BulkOperation, error) {
+	options := []func(*Document){
+		WithIndex(d.Index),
+		WithDocumentID(d.ID),
+		WithDocumentRouting(d.Routing),
+		WithDocumentPipeline(d.Pipeline),
+		WithContent(d.Content),
+	}
+
+	switch d.Action {
+	case BulkActionIndex:
+		return BulkIndex(NewDocument(options...)), nil
+	case BulkActionCreate:
+		return BulkCreate(NewDocument(options...)), nil
+	case BulkActionDelete:
+		return BulkDelete(NewDocument(options...)), nil
+	case BulkActionUpdate:
+		var source struct {
+			Doc            json.RawMessage `json:"doc"`
+			DocAsUpsert    *bool           `json:"doc_as_upsert"`
+			Script         *Script         `json:"script"`
+			ScriptedUpsert *bool           `json:"scripted_upsert"`
+			Upsert         json.RawMessage `json:"upsert"`
+		}
+
+		if len(d.Source) == 0 {
+			return BulkUpdate(NewDocument(options...)), nil
+		}
+
+		if err := json.Unmarshal(d.Source, &source); err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode dead letter source: %w", err)
+		}
+
+		var updateOptions []func(*BulkOperation)
+		if source.DocAsUpsert != nil {
+			updateOptions = append(updateOptions, WithBulkDocAsUpsert(*source.DocAsUpsert))
+		}
+
+		if source.Script != nil {
+			updateOptions = append(updateOptions, WithBulkScript(*source.Script))
+		}
+
+		if source.ScriptedUpsert != nil {
+			updateOptions = append(updateOptions, WithBulkScriptedUpsert(*source.ScriptedUpsert))
+		}
+
+		if len(source.Upsert) > 0 {
+			updateOptions = append(updateOptions, WithBulkUpsert(source.Upsert))
+		}
+		return BulkUpdate(NewDocument(append(options, WithContent(source.Doc))...), updateOptions...), nil
+	}
+	return nil, fmt.Errorf("opensearch: unsupported dead letter action %q: %w", d.Action, ErrInvalid)
+}
+
+// String returns a string representation of the DeadLetter.
+func (d *DeadLetter) String() string {
+	return string(anchor.ToJSONFormatted(d))
+}
+
+// DeadLetterSink receives the bulk items that permanently failed. See WithBulkIndexerDeadLetterSink.
+type DeadLetterSink interface {
+	// Write writes the provided dead letters to the sink.
+	Write(ctx context.Context, letters ...*DeadLetter) error
+
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// FileDeadLetterSink is a DeadLetterSink that appends dead letters to a file in NDJSON format, which can be read using
+// ReadDeadLetters.
+type FileDeadLetterSink struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// NewFileDeadLetterSink creates a new FileDeadLetterSink that appends to the file at the provided path, creating it
+// if it does not exist.
+func NewFileDeadLetterSink(path string) (*FileDeadLetterSink, error) {
+	f, err := os.OpenFile(strings.TrimSpace(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDeadLetterSink{file: f}, nil
+}
+
+// Write appends the provided dead letters to the file, one per line.
+func (s *FileDeadLetterSink) Write(_ context.Context, letters ...*DeadLetter) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return ErrClosed
+	}
+
+	w := bufio.NewWriter(s.file)
+	for _, d := range letters {
+		b, err := json.Marshal(d)
+		if err != nil {
+			return fmt.Errorf("opensearch: could not encode dead letter: %w", err)
+		}
+
+		w.Write(b)
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
+
+// Close closes the file.
+func (s *FileDeadLetterSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return ErrClosed
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// ReadDeadLetters reads the dead letters in NDJSON format from the provided reader, e.g. a file written by
+// FileDeadLetterSink.
+func ReadDeadLetters(reader io.Reader) ([]*DeadLetter, error) {
+	var letters []*DeadLetter
+	decoder := json.NewDecoder(reader)
+	for decoder.More() {
+		d := &DeadLetter{}
+		if err := decoder.Decode(d); err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode dead letter: %w", err)
+		}
+		letters = append(letters, d)
+	}
+	return letters, nil
+}
+
+// IndexDeadLetterSink is a DeadLetterSink that indexes dead letters into an OpenSearch index. The index is created on
+// the first write if it does not exist, with the document content and source stored but not indexed so the original
+// mapping failure cannot reoccur.
+type IndexDeadLetterSink struct {
+	created    bool
+	index      string
+	mutex      sync.Mutex
+	repository *Repository
+}
+
+// NewIndexDeadLetterSink creates a new IndexDeadLetterSink that writes to the provided index using the Repository.
+func NewIndexDeadLetterSink(repository *Repository, index string) (*IndexDeadLetterSink, error) {
+	index = strings.TrimSpace(index)
+	if repository == nil || index == "" {
+		return nil, ErrInvalid
+	}
+	return &IndexDeadLetterSink{index: index, repository: repository}, nil
+}
+
+// Write indexes the provided dead letters.
+func (s *IndexDeadLetterSink) Write(ctx context.Context, letters ...*DeadLetter) error {
+	if err := s.create(ctx); err != nil {
+		return err
+	}
+
+	ops := make([]*BulkOperation, len(letters))
+	for i, d := range letters {
+		ops[i] = BulkIndex(NewDocument(WithIndex(s.index), WithContent(anchor.ToJSON(d))))
+	}
+
+	result, err := s.repository.Bulk(ctx, ops...)
+	if err != nil {
+		return err
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return fmt.Errorf("opensearch: could not write %d dead letters: %w", len(failed), failed[0].Error)
+	}
+	return nil
+}
+
+// create creates the index if it does not exist. If creation fails, it is retried by the next write.
+func (s *IndexDeadLetterSink) create(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.created {
+		return nil
+	}
+
+	err := createIndex(ctx, s.repository.Indices(), s.index, WithIndexMappings(map[string]any{
+		"properties": map[string]any{
+			"@timestamp":   map[string]any{"type": "date"},
+			"action":       map[string]any{"type": "keyword"},
+			"index":        map[string]any{"type": "keyword"},
+			"id":           map[string]any{"type": "keyword"},
+			"routing":      map[string]any{"type": "keyword"},
+			"pipeline":     map[string]any{"type": "keyword"},
+			"content":      map[string]any{"type": "object", "enabled": false},
+			"source":       map[string]any{"type": "object", "enabled": false},
+			"status":       map[string]any{"type": "integer"},
+			"error_type":   map[string]any{"type": "keyword"},
+			"error_reason": map[string]any{"type": "text"},
+			"attempts":     map[string]any{"type": "integer"},
+		},
+	}))
+	if err != nil {
+		return err
+	}
+
+	s.created = true
+	return nil
+}
+
+// Read returns the dead letters in the index.
+func (s *IndexDeadLetterSink) Read(ctx context.Context) ([]*DeadLetter, error) {
+	result, err := s.repository.Search(ctx, s.index, WithMatchAll(true))
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]*DeadLetter, 0, len(result.Documents))
+	for _, doc := range result.Documents {
+		d := &DeadLetter{}
+		if err := json.Unmarshal(doc.Content(), d); err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode dead letter %s: %w", doc.ID(), err)
+		}
+		letters = append(letters, d)
+	}
+	return letters, nil
+}
+
+// Close is a no-op, since the Repository is owned by the caller.
+func (s *IndexDeadLetterSink) Close() error {
+	return nil
+}
+
+// ReplayDeadLetters re-submits the provided dead letters using Repository.Bulk and returns the result of each, in the
+// same order.
+func (r *Repository) ReplayDeadLetters(ctx context.Context, letters ...*DeadLetter) (*BulkResult, error) {
+	ops := make([]*BulkOperation, len(letters))
+	for i, d := range letters {
+		op, err := d.Operation()
+		if err != nil {
+			return nil, err
+		}
+		ops[i] = op
+	}
+
+	log.Info("[opensearch] replaying dead letters", log.Int("count", len(ops)))
+
+	return r.Bulk(ctx, ops...)
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	json "github.com/json-iterator/go"
+)
+
+func TestDeadLetterOperation(t *testing.T) {
+	content := WithContent(json.RawMessage(`{"name":"test"}`))
+
+	tests := []struct {
+		name string
+		op   *BulkOperation
+	}{
+		{
+			name: "index",
+			op:   BulkIndex(NewDocument(WithIndex("test"), WithDocumentID("1"), content)),
+		},
+		{
+			name: "index with routing and pipeline",
+			op: BulkIndex(NewDocument(WithIndex("test"), content),
+				WithBulkRouting("shard"),
+				WithBulkPipeline("pipeline")),
+		},
+		{
+			name: "create",
+			op:   BulkCreate(NewDocument(WithIndex("test"), WithDocumentID("1"), content)),
+		},
+		{
+			name: "update",
+			op: BulkUpdate(NewDocument(WithIndex("test"), WithDocumentID("1"), content),
+				WithBulkDocAsUpsert(true)),
+		},
+		{
+			name: "update with script",
+			op: BulkUpdate(NewDocument(WithIndex("test"), WithDocumentID("1")),
+				WithBulkScript(Script{Source: "ctx._source.n += params.n", Lang: "painless", Params: map[string]any{"n": 1}}),
+				WithBulkScriptedUpsert(true),
+				WithBulkUpsert(json.RawMessage(`{"n":0}`))),
+		},
+		{
+			name: "delete",
+			op:   BulkDelete(NewDocument(WithIndex("test"), WithDocumentID("1"), WithDocumentRouting("shard"))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			itemErr := &BulkItemError{Action: tt.op.Action(), Status: 400, Type: "mapper_parsing_exception", Reason: "failed"}
+			d := newDeadLetter(tt.op, itemErr, 2)
+
+			if d.Status != itemErr.Status || d.ErrorType != itemErr.Type || d.ErrorReason != itemErr.Reason || d.Attempts != 2 {
+				t.Fatalf("expected error details of %v, got: %+v", itemErr, d)
+			}
+
+			// round-trip through JSON as done by the DeadLetterSink implementations
+			replayed := &DeadLetter{}
+			if err := json.Unmarshal(marshal(t, d), replayed); err != nil {
+				t.Fatalf("could not decode dead letter: %v", err)
+			}
+
+			op, err := replayed.Operation()
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if op.Action() != tt.op.Action() {
+				t.Fatalf("expected action %s, got %s", tt.op.Action(), op.Action())
+			}
+			assertEncodedEqual(t, tt.op, op)
+		})
+	}
+}
+
+func TestDeadLetterOperationUnsupportedAction(t *testing.T) {
+	d := &DeadLetter{Action: "upsert", Index: "test"}
+	if _, err := d.Operation(); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected error wrapping %v, got: %v", ErrInvalid, err)
+	}
+}
+
+func marshal(t *testing.T, v any) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("could not encode %T: %v", v, err)
+	}
+	return b
+}
+
+// assertEncodedEqual asserts that the NDJSON lines of the operations are equal, regardless of the order of their keys.
+func assertEncodedEqual(t *testing.T, expected *BulkOperation, actual *BulkOperation) {
+	t.Helper()
+
+	decode := func(op *BulkOperation) []any {
+		var buf bytes.Buffer
+		if err := op.encode(&buf); err != nil {
+			t.Fatalf("could not encode bulk %s: %v", op.Action(), err)
+		}
+
+		var lines []any
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			var v any
+			if err := json.Unmarshal([]byte(line), &v); err != nil {
+				t.Fatalf("could not decode line %q: %v", line, err)
+			}
+			lines = append(lines, v)
+		}
+		return lines
+	}
+
+	if e, a := decode(expected), decode(actual); !reflect.DeepEqual(e, a) {
+		t.Fatalf("expected encoded operation %v, got %v", e, a)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"bytes"
 	"io"
+	"strings"
 
 	"github.com/transientvariable/anchor"
 
@@ -11,10 +12,12 @@ import (
 
 // Document defines the container for documents maintained in a document store.
 type Document struct {
-	content json.RawMessage
-	id      string
-	index   string
-	sort    []any
+	content  json.RawMessage
+	id       string
+	index    string
+	pipeline string
+	routing  string
+	sort     []any
 }
 
 // NewDocument creates a new Document that represents data to be indexed or the result from a query.
@@ -47,11 +50,23 @@ func (d *Document) Index() string {
 	return d.index
 }
 
+// Pipeline returns the ID of the ingest pipeline used to preprocess the Document when it is created, which can be the
+// zero value for string.
+func (d *Document) Pipeline() string {
+	return d.pipeline
+}
+
 // Reader returns an io.Reader for the Document.Content().
 func (d *Document) Reader() io.ReadSeeker {
 	return bytes.NewReader(d.Content())
 }
 
+// Routing returns the custom routing value used for directing the Document to a specific shard, which can be the zero
+// value for string.
+func (d *Document) Routing() string {
+	return d.routing
+}
+
 // Sort returns the Document sort values.
 func (d *Document) Sort() []any {
 	if len(d.sort) > 0 {
@@ -69,6 +84,14 @@ func (d *Document) String() string {
 		"index": d.Index(),
 	}
 
+	if d.Pipeline() != "" {
+		dm["pipeline"] = d.Pipeline()
+	}
+
+	if d.Routing() != "" {
+		dm["routing"] = d.Routing()
+	}
+
 	if len(d.Content()) > 0 {
 		var cm map[string]any
 		if err := json.NewDecoder(d.Reader()).Decode(&cm); err != nil {
@@ -102,6 +125,22 @@ func WithDocumentID(id string) func(*Document) {
 	}
 }
 
+// WithDocumentPipeline sets the ID of the ingest pipeline used to preprocess the Document when it is created using
+// Repository.Create, or by a bulk index or create operation.
+func WithDocumentPipeline(pipeline string) func(*Document) {
+	return func(document *Document) {
+		document.pipeline = strings.TrimSpace(pipeline)
+	}
+}
+
+// WithDocumentRouting sets the Document custom routing value. The same routing value must be provided for subsequent
+// operations on the Document.
+func WithDocumentRouting(routing string) func(*Document) {
+	return func(document *Document) {
+		document.routing = strings.TrimSpace(routing)
+	}
+}
+
 // WithDocumentSort sets the Document sort values.
 func WithDocumentSort(sort ...any) func(*Document) {
 	return func(document *Document) {
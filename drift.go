@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+
+	json "github.com/json-iterator/go"
+)
+
+const (
+	// DriftAdded denotes a field or setting that is present in the cluster but not declared on disk.
+	DriftAdded = "added"
+
+	// DriftRemoved denotes a field or setting that is declared on disk but missing from the cluster.
+	DriftRemoved = "removed"
+
+	// DriftChanged denotes a field or setting whose value in the cluster differs from the value declared on disk.
+	DriftChanged = "changed"
+
+	DriftSectionMappings = "mappings"
+	DriftSectionSettings = "settings"
+	DriftSectionTemplate = "template"
+
+	DriftScopeComponentTemplate = TemplateTypeComponent
+	DriftScopeIndexTemplate     = TemplateTypeIndex
+	DriftScopeIndex             = "index"
+
+	driftRootField = "_root"
+)
+
+// Drift describes a single difference between the templates declared on disk and the state of the cluster.
+type Drift struct {
+	Scope    string `json:"scope"`
+	Target   string `json:"target"`
+	Section  string `json:"section"`
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   any    `json:"actual,omitempty"`
+}
+
+// DriftReport is a container for the differences detected between the templates declared on disk and the state of the
+// cluster.
+type DriftReport struct {
+	Drifts []*Drift `json:"drifts,omitempty"`
+}
+
+// HasDrift returns whether any differences were detected.
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Drifts) > 0
+}
+
+// Targets returns the sorted names of the templates and indices with detected differences.
+func (r *DriftReport) Targets() []string {
+	seen := make(map[string]bool)
+	var targets []string
+	for _, d := range r.Drifts {
+		if !seen[d.Target] {
+			seen[d.Target] = true
+			targets = append(targets, d.Target)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// String returns a string representation of the DriftReport.
+func (r *DriftReport) String() string {
+	return string(anchor.ToJSONFormatted(r))
+}
+
+// DetectDrift compares the component and index templates read from the `ecs` and `index` subdirectories of the
+// provided path with the templates installed in the cluster, and the effective mappings and settings of each index
+// template with the live indices matching its `index_patterns`.
+//
+// For live indices, settings that are not declared by the templates are ignored, since the cluster assigns many
+// settings automatically.
+func (r *Repository) DetectDrift(ctx context.Context, templatePath string) (*DriftReport, error) {
+	return r.DetectDriftFS(ctx, dirFS(templatePath), ".")
+}
+
+// DetectDriftFS is the same as DetectDrift, but reads the templates from the provided directory of fsys.
+func (r *Repository) DetectDriftFS(ctx context.Context, fsys fs.FS, dir string) (*DriftReport, error) {
+	componentTemplates, err := ReadTemplatesFS(fsys, path.Join(dir, TemplateDirNameECS))
+	if err != nil {
+		return nil, err
+	}
+
+	indexTemplates, err := ReadTemplatesFS(fsys, path.Join(dir, TemplateDirNameIndex))
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := orderTemplates(append(componentTemplates, indexTemplates...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &driftDetector{
+		client:     r.client,
+		components: make(map[string]templateBody),
+		indices:    r.Indices(),
+		report:     &DriftReport{},
+	}
+
+	for _, t := range templates {
+		expected, err := decodeTemplateBody(t.content)
+		if err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode %s %s: %w", t.Type(), t.Path(), err)
+		}
+
+		switch t.Type() {
+		case TemplateTypeComponent:
+			d.components[t.Name()] = expected
+			err = d.component(ctx, t.Name(), expected)
+		case TemplateTypeIndex:
+			err = d.index(ctx, t.Name(), expected)
+		default:
+			log.Debug("[opensearch] skipping drift detection for template",
+				log.String("name", t.Name()),
+				log.String("type", t.Type()))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Debug(fmt.Sprintf("[opensearch] drift report:\n%s", d.report))
+
+	return d.report, nil
+}
+
+// templateBody is the representation of a composable index template or component template.
+type templateBody struct {
+	IndexPatterns []string `json:"index_patterns,omitempty"`
+	ComposedOf    []string `json:"composed_of,omitempty"`
+	Template      struct {
+		Settings map[string]any `json:"settings,omitempty"`
+		Mappings map[string]any `json:"mappings,omitempty"`
+	} `json:"template"`
+}
+
+func decodeTemplateBody(content []byte) (templateBody, error) {
+	var t templateBody
+	err := json.Unmarshal(content, &t)
+	return t, err
+}
+
+type driftDetector struct {
+	client     *opensearch.Client
+	components map[string]templateBody
+	indices    *IndexService
+	report     *DriftReport
+}
+
+func (d *driftDetector) component(ctx context.Context, name string, expected templateBody) error {
+	var e struct {
+		ComponentTemplates []struct {
+			ComponentTemplate templateBody `json:"component_template"`
+		} `json:"component_templates"`
+	}
+	err := do(ctx, d.client, "get component template", opensearchapi.ClusterGetComponentTemplateRequest{
+		Name: []string{name},
+	}, &e)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if len(e.ComponentTemplates) == 0 {
+		d.add(DriftScopeComponentTemplate, name, DriftSectionTemplate, name, DriftRemoved, name, nil)
+		return nil
+	}
+
+	actual := e.ComponentTemplates[0].ComponentTemplate
+	d.compareMappings(DriftScopeComponentTemplate, name, expected.Template.Mappings, actual.Template.Mappings)
+	d.compareSettings(DriftScopeComponentTemplate, name, expected.Template.Settings, actual.Template.Settings, true)
+	return nil
+}
+
+func (d *driftDetector) index(ctx context.Context, name string, expected templateBody) error {
+	var e struct {
+		IndexTemplates []struct {
+			IndexTemplate templateBody `json:"index_template"`
+		} `json:"index_templates"`
+	}
+	err := do(ctx, d.client, "get index template", opensearchapi.IndicesGetIndexTemplateRequest{
+		Name: []string{name},
+	}, &e)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if len(e.IndexTemplates) == 0 {
+		d.add(DriftScopeIndexTemplate, name, DriftSectionTemplate, name, DriftRemoved, name, nil)
+	} else {
+		actual := e.IndexTemplates[0].IndexTemplate
+		if !equalValues(expected.IndexPatterns, actual.IndexPatterns) {
+			d.add(DriftScopeIndexTemplate, name, DriftSectionTemplate, "index_patterns", DriftChanged,
+				expected.IndexPatterns, actual.IndexPatterns)
+		}
+
+		if !equalValues(expected.ComposedOf, actual.ComposedOf) {
+			d.add(DriftScopeIndexTemplate, name, DriftSectionTemplate, "composed_of", DriftChanged,
+				expected.ComposedOf, actual.ComposedOf)
+		}
+		d.compareMappings(DriftScopeIndexTemplate, name, expected.Template.Mappings, actual.Template.Mappings)
+		d.compareSettings(DriftScopeIndexTemplate, name, expected.Template.Settings, actual.Template.Settings, true)
+	}
+
+	if len(expected.IndexPatterns) == 0 {
+		return nil
+	}
+
+	mappings, settings, err := d.effective(ctx, expected)
+	if err != nil {
+		return err
+	}
+
+	liveMappings, err := d.indices.Mapping(ctx, expected.IndexPatterns...)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	liveSettings, err := d.indices.Settings(ctx, expected.IndexPatterns...)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	indices := make([]string, 0, len(liveMappings))
+	for index := range liveMappings {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	for _, index := range indices {
+		d.compareMappings(DriftScopeIndex, index, mappings, liveMappings[index])
+		d.compareSettings(DriftScopeIndex, index, settings, liveSettings[index], false)
+	}
+	return nil
+}
+
+// effective returns the mappings and settings an index created from the index template would have, merging the
+// referenced component templates in order followed by the index template itself.
+func (d *driftDetector) effective(ctx context.Context, t templateBody) (map[string]any, map[string]any, error) {
+	mappings := make(map[string]any)
+	settings := make(map[string]any)
+	for _, name := range t.ComposedOf {
+		c, ok := d.components[name]
+		if !ok {
+			var e struct {
+				ComponentTemplates []struct {
+					ComponentTemplate templateBody `json:"component_template"`
+				} `json:"component_templates"`
+			}
+			err := do(ctx, d.client, "get component template", opensearchapi.ClusterGetComponentTemplateRequest{
+				Name: []string{name},
+			}, &e)
+			if err != nil {
+				return nil, nil, fmt.Errorf("opensearch: could not resolve component template %s: %w", name, err)
+			}
+
+			if len(e.ComponentTemplates) > 0 {
+				c = e.ComponentTemplates[0].ComponentTemplate
+			}
+		}
+		mergeMaps(mappings, c.Template.Mappings)
+		mergeMaps(settings, flattenSettings("", c.Template.Settings))
+	}
+	mergeMaps(mappings, t.Template.Mappings)
+	mergeMaps(settings, flattenSettings("", t.Template.Settings))
+	return mappings, settings, nil
+}
+
+func (d *driftDetector) compareMappings(scope string, target string, expected map[string]any, actual map[string]any) {
+	e := flattenMappings("", expected, make(map[string]map[string]any))
+	a := flattenMappings("", actual, make(map[string]map[string]any))
+
+	for _, field := range sortedKeys(e) {
+		af, ok := a[field]
+		if !ok {
+			d.add(scope, target, DriftSectionMappings, field, DriftRemoved, e[field], nil)
+			continue
+		}
+
+		for _, param := range sortedKeys(e[field]) {
+			if !equalValues(e[field][param], af[param]) {
+				d.add(scope, target, DriftSectionMappings, field+"."+param, DriftChanged, e[field][param], af[param])
+			}
+		}
+	}
+
+	for _, field := range sortedKeys(a) {
+		if _, ok := e[field]; !ok && field != driftRootField {
+			d.add(scope, target, DriftSectionMappings, field, DriftAdded, nil, a[field])
+		}
+	}
+}
+
+func (d *driftDetector) compareSettings(scope string, target string, expected map[string]any, actual map[string]any, reportAdded bool) {
+	e := flattenSettings("", expected)
+	a := flattenSettings("", actual)
+
+	for _, key := range sortedKeys(e) {
+		v, ok := a[key]
+		switch {
+		case !ok:
+			d.add(scope, target, DriftSectionSettings, key, DriftRemoved, e[key], nil)
+		case !equalValues(e[key], v):
+			d.add(scope, target, DriftSectionSettings, key, DriftChanged, e[key], v)
+		}
+	}
+
+	if reportAdded {
+		for _, key := range sortedKeys(a) {
+			if _, ok := e[key]; !ok {
+				d.add(scope, target, DriftSectionSettings, key, DriftAdded, nil, a[key])
+			}
+		}
+	}
+}
+
+func (d *driftDetector) add(scope string, target string, section string, path string, kind string, expected any, actual any) {
+	d.report.Drifts = append(d.report.Drifts, &Drift{
+		Scope:    scope,
+		Target:   target,
+		Section:  section,
+		Path:     path,
+		Kind:     kind,
+		Expected: expected,
+		Actual:   actual,
+	})
+}
+
+// flattenMappings flattens the mapping into a map of dotted field paths to the mapping parameters of each field,
+// including multi-fields. Top-level parameters (e.g. `dynamic`, `_meta`) are keyed by driftRootField and are only
+// compared when declared on disk, since the cluster adds some (e.g. `_data_stream_timestamp`) automatically.
+func flattenMappings(path string, mapping map[string]any, fields map[string]map[string]any) map[string]map[string]any {
+	params := make(map[string]any)
+	for k, v := range mapping {
+		switch k {
+		case "properties", "fields":
+			if children, ok := v.(map[string]any); ok {
+				for name, child := range children {
+					if c, ok := child.(map[string]any); ok {
+						p := name
+						if path != "" {
+							p = path + "." + name
+						}
+						flattenMappings(p, c, fields)
+					}
+				}
+			}
+		default:
+			params[k] = v
+		}
+	}
+
+	if path == "" {
+		if len(params) > 0 {
+			fields[driftRootField] = params
+		}
+	} else {
+		fields[path] = params
+	}
+	return fields
+}
+
+// flattenSettings flattens the settings into a map of dotted setting names without the leading `index.` prefix.
+func flattenSettings(prefix string, settings map[string]any) map[string]any {
+	flat := make(map[string]any)
+	for k, v := range settings {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if m, ok := v.(map[string]any); ok {
+			for fk, fv := range flattenSettings(key, m) {
+				flat[fk] = fv
+			}
+			continue
+		}
+		flat[strings.TrimPrefix(key, "index.")] = v
+	}
+	return flat
+}
+
+// mergeMaps recursively merges the source map into the destination map, where values in the source take precedence.
+func mergeMaps(dst map[string]any, src map[string]any) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				mergeMaps(dm, sm)
+				continue
+			}
+
+			m := make(map[string]any)
+			mergeMaps(m, sm)
+			dst[k] = m
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// equalValues returns whether the values are equal, comparing scalar values by their string representation since the
+// cluster returns some values (e.g. settings) as strings.
+func equalValues(a any, b any) bool {
+	a, b = normalize(a), normalize(b)
+	switch a.(type) {
+	case map[string]any, []any:
+		return reflect.DeepEqual(a, b)
+	}
+
+	switch b.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// normalize converts the value to its generic JSON representation so typed values (e.g. []string) compare equal to
+// their decoded counterparts.
+func normalize(v any) any {
+	var n any
+	if err := json.Unmarshal(anchor.ToJSON(v), &n); err != nil {
+		return v
+	}
+	return n
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	json "github.com/json-iterator/go"
+)
+
+func TestFlattenSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		expected map[string]any
+	}{
+		{
+			name:     "empty",
+			settings: `{}`,
+			expected: map[string]any{},
+		},
+		{
+			name:     "nested under index",
+			settings: `{"index":{"number_of_shards":"1","refresh_interval":"1s"}}`,
+			expected: map[string]any{"number_of_shards": "1", "refresh_interval": "1s"},
+		},
+		{
+			name:     "dotted keys",
+			settings: `{"index.number_of_shards":1,"index.lifecycle.name":"policy"}`,
+			expected: map[string]any{"number_of_shards": float64(1), "lifecycle.name": "policy"},
+		},
+		{
+			name:     "mixed",
+			settings: `{"index":{"mapping":{"total_fields":{"limit":"2000"}}},"index.codec":"best_compression"}`,
+			expected: map[string]any{"mapping.total_fields.limit": "2000", "codec": "best_compression"},
+		},
+		{
+			name:     "without index prefix",
+			settings: `{"number_of_replicas":0,"analysis":{"analyzer":{"default":{"type":"standard"}}}}`,
+			expected: map[string]any{"number_of_replicas": float64(0), "analysis.analyzer.default.type": "standard"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := flattenSettings("", decodeMap(t, tt.settings))
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFlattenMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  string
+		expected map[string]map[string]any
+	}{
+		{
+			name:     "empty",
+			mapping:  `{}`,
+			expected: map[string]map[string]any{},
+		},
+		{
+			name:    "properties",
+			mapping: `{"properties":{"name":{"type":"keyword"},"count":{"type":"long","index":false}}}`,
+			expected: map[string]map[string]any{
+				"name":  {"type": "keyword"},
+				"count": {"type": "long", "index": false},
+			},
+		},
+		{
+			name:    "nested properties and multi-fields",
+			mapping: `{"properties":{"user":{"properties":{"name":{"type":"text","fields":{"raw":{"type":"keyword"}}}}}}}`,
+			expected: map[string]map[string]any{
+				"user":          {},
+				"user.name":     {"type": "text"},
+				"user.name.raw": {"type": "keyword"},
+			},
+		},
+		{
+			name:    "root parameters",
+			mapping: `{"dynamic":"strict","_meta":{"version":1},"properties":{"name":{"type":"keyword"}}}`,
+			expected: map[string]map[string]any{
+				driftRootField: {"dynamic": "strict", "_meta": map[string]any{"version": float64(1)}},
+				"name":         {"type": "keyword"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := flattenMappings("", decodeMap(t, tt.mapping), make(map[string]map[string]any))
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEqualValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        any
+		b        any
+		expected bool
+	}{
+		{name: "equal strings", a: "1s", b: "1s", expected: true},
+		{name: "different strings", a: "1s", b: "5s", expected: false},
+		{name: "int and string", a: 1, b: "1", expected: true},
+		{name: "float and string", a: float64(2), b: "2", expected: true},
+		{name: "bool and string", a: true, b: "true", expected: true},
+		{name: "typed and decoded slices", a: []string{"a", "b"}, b: []any{"a", "b"}, expected: true},
+		{name: "slices in different order", a: []string{"a", "b"}, b: []any{"b", "a"}, expected: false},
+		{name: "typed and decoded maps", a: map[string]string{"k": "v"}, b: map[string]any{"k": "v"}, expected: true},
+		{name: "scalar and slice", a: "a", b: []any{"a"}, expected: false},
+		{name: "scalar and missing", a: "a", b: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := equalValues(tt.a, tt.b); actual != tt.expected {
+				t.Fatalf("expected %t, got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDriftDetectorCompare(t *testing.T) {
+	tests := []struct {
+		name        string
+		section     string
+		expected    string
+		actual      string
+		reportAdded bool
+		drifts      []*Drift
+	}{
+		{
+			name:     "equal settings",
+			section:  DriftSectionSettings,
+			expected: `{"index":{"number_of_shards":1}}`,
+			actual:   `{"index":{"number_of_shards":"1","uuid":"abc"}}`,
+		},
+		{
+			name:        "settings",
+			section:     DriftSectionSettings,
+			expected:    `{"index":{"number_of_shards":1,"refresh_interval":"1s"}}`,
+			actual:      `{"index":{"number_of_shards":"2","codec":"best_compression"}}`,
+			reportAdded: true,
+			drifts: []*Drift{
+				{Path: "number_of_shards", Kind: DriftChanged, Expected: float64(1), Actual: "2"},
+				{Path: "refresh_interval", Kind: DriftRemoved, Expected: "1s"},
+				{Path: "codec", Kind: DriftAdded, Actual: "best_compression"},
+			},
+		},
+		{
+			name:     "mappings",
+			section:  DriftSectionMappings,
+			expected: `{"dynamic":"strict","properties":{"name":{"type":"keyword"},"age":{"type":"integer"}}}`,
+			actual:   `{"dynamic":"strict","_data_stream_timestamp":{"enabled":true},"properties":{"name":{"type":"text"},"email":{"type":"keyword"}}}`,
+			drifts: []*Drift{
+				{Path: "age", Kind: DriftRemoved, Expected: map[string]any{"type": "integer"}},
+				{Path: "name.type", Kind: DriftChanged, Expected: "keyword", Actual: "text"},
+				{Path: "email", Kind: DriftAdded, Actual: map[string]any{"type": "keyword"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &driftDetector{report: &DriftReport{}}
+			if tt.section == DriftSectionSettings {
+				d.compareSettings(DriftScopeIndex, "test", decodeMap(t, tt.expected), decodeMap(t, tt.actual), tt.reportAdded)
+			} else {
+				d.compareMappings(DriftScopeIndex, "test", decodeMap(t, tt.expected), decodeMap(t, tt.actual))
+			}
+
+			for _, drift := range tt.drifts {
+				drift.Scope = DriftScopeIndex
+				drift.Target = "test"
+				drift.Section = tt.section
+			}
+
+			if !reflect.DeepEqual(d.report.Drifts, tt.drifts) {
+				t.Fatalf("expected drifts:\n%s\ngot:\n%s", &DriftReport{Drifts: tt.drifts}, d.report)
+			}
+		})
+	}
+}
+
+func decodeMap(t *testing.T, s string) map[string]any {
+	t.Helper()
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatalf("could not decode %s: %v", s, err)
+	}
+	return m
+}
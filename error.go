@@ -1,6 +1,11 @@
 package repository
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+
+	json "github.com/json-iterator/go"
+)
 
 // opensearchError defines the type for errors that may be returned by OpenSearch repository operations.
 type opensearchError string
@@ -14,8 +19,15 @@ func (e opensearchError) Error() string {
 const (
 	ErrMalformedIndex           = opensearchError("index is missing or malformed")
 	ErrMalformedDocumentContent = opensearchError("document content is missing or malformed")
-	ErrClosed  = opensearchError("repository already closed")
-	ErrInvalid = opensearchError("invalid argument")
+	ErrClosed                   = opensearchError("repository already closed")
+	ErrInvalid                  = opensearchError("invalid argument")
+	ErrNotFound                 = opensearchError("resource not found")
+	ErrExists                   = opensearchError("resource already exists")
+	ErrConflict                 = opensearchError("version conflict")
+	ErrMigrationLocked          = opensearchError("migration lock is held by another instance")
+	ErrMigrationLockLost        = opensearchError("migration lock is no longer held")
+	ErrMigrationChecksum        = opensearchError("applied migration has been modified")
+	ErrMigrationIrreversible    = opensearchError("migration does not define down steps")
 )
 
 // QueryError defines the error type for errors returned from a document repository resulting from an invalid or
@@ -29,3 +41,105 @@ type QueryError struct {
 func (e *QueryError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Operation, e.Message)
 }
+
+// ResponseError defines the error type for errors returned by an OpenSearch cluster in response to a request.
+type ResponseError struct {
+	Operation  string
+	StatusCode int
+	Type       string
+	Reason     string
+}
+
+// Error returns the cause of the ResponseError error.
+func (e *ResponseError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("%s: [%d %s] %s: %s", e.Operation, e.StatusCode, http.StatusText(e.StatusCode), e.Type, e.Reason)
+	}
+	return fmt.Sprintf("%s: [%d %s] %s", e.Operation, e.StatusCode, http.StatusText(e.StatusCode), e.Reason)
+}
+
+// Unwrap returns ErrNotFound or ErrExists if the ResponseError denotes a missing or already existing resource,
+// respectively, which allows for checking the cause using errors.Is.
+func (e *ResponseError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.Type == "resource_already_exists_exception":
+		return ErrExists
+	}
+	return nil
+}
+
+// BulkItemError defines the error type for a failed operation of a bulk request.
+type BulkItemError struct {
+	Action string
+	Index  string
+	ID     string
+	Status int
+	Type   string
+	Reason string
+}
+
+// Error returns the cause of the BulkItemError error.
+func (e *BulkItemError) Error() string {
+	return fmt.Sprintf("bulk %s %s/%s: [%d %s] %s: %s", e.Action, e.Index, e.ID, e.Status, http.StatusText(e.Status),
+		e.Type, e.Reason)
+}
+
+// Unwrap returns ErrNotFound or ErrConflict if the BulkItemError denotes a missing document or a version conflict,
+// respectively, which allows for checking the cause using errors.Is.
+func (e *BulkItemError) Unwrap() error {
+	switch e.Status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	}
+	return nil
+}
+
+// TaskError defines the error type for errors reported by a task that was executed asynchronously, either as the
+// error of the task or as the per-document failures of its response (e.g. for reindex, update-by-query, and
+// delete-by-query tasks). Type and Reason describe the error of the task or, if it has none, its first failure.
+type TaskError struct {
+	ID       string
+	Type     string
+	Reason   string
+	Failures []map[string]any
+}
+
+// Error returns the cause of the TaskError error.
+func (e *TaskError) Error() string {
+	if len(e.Failures) > 1 {
+		return fmt.Sprintf("task %s: %s: %s (and %d more failures)", e.ID, e.Type, e.Reason, len(e.Failures)-1)
+	}
+	return fmt.Sprintf("task %s: %s: %s", e.ID, e.Type, e.Reason)
+}
+
+// newTaskError returns the TaskError for the completed task, or nil if the task reported neither an error nor
+// failures.
+func newTaskError(status *TaskStatus) *TaskError {
+	var r struct {
+		Failures []map[string]any `json:"failures"`
+	}
+	if len(status.Response) > 0 {
+		_ = json.Unmarshal(status.Response, &r)
+	}
+
+	if len(status.Error) == 0 && len(r.Failures) == 0 {
+		return nil
+	}
+
+	e := &TaskError{ID: status.ID, Failures: r.Failures}
+	cause := status.Error
+	if len(cause) == 0 {
+		// Document failures report their cause under `cause`, whereas search failures report it under `reason`.
+		cause, _ = r.Failures[0]["cause"].(map[string]any)
+		if cause == nil {
+			cause, _ = r.Failures[0]["reason"].(map[string]any)
+		}
+	}
+	e.Type, _ = cause["type"].(string)
+	e.Reason, _ = cause["reason"].(string)
+	return e
+}
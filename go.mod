@@ -7,13 +7,12 @@ require (
 	github.com/json-iterator/go v1.1.12
 	github.com/opensearch-project/opensearch-go v1.1.0
 	github.com/transientvariable/anchor v0.0.0-20250331040147-31a7b773ebd9
-	github.com/transientvariable/cadre v0.0.0-20250408192700-9bf4f9e2e15c
 	github.com/transientvariable/log-go v0.0.0-20250331030700-56e504a9bfbc
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/dustin/go-humanize v1.0.1 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/ipfs/go-cid v0.5.0 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.10 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
@@ -36,6 +35,5 @@ require (
 	golang.org/x/exp v0.0.0-20250408133849-7e4ce0ab07d0 // indirect
 	golang.org/x/sys v0.32.0 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	lukechampine.com/blake3 v1.4.0 // indirect
 )
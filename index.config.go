@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/transientvariable/anchor"
+
+	json "github.com/json-iterator/go"
+)
+
+const (
+	IndexTypeDataStream = "data_stream"
+	IndexTypeIndex      = "index"
+)
+
+// IndicesConfig is the configuration of the data streams and indices created when bootstrapping the Repository. See
+// WithIndicesPath.
+type IndicesConfig struct {
+	DataStreams    []*DataStreamConfig `json:"data_streams,omitempty"`
+	Indices        []*IndexConfig      `json:"indices,omitempty"`
+	DerivedIndices []*DerivedIndexRule `json:"derived_indices,omitempty"`
+}
+
+// derive returns the configurations of the companion indices for the provided index declared by its `companions` and
+// the matching DerivedIndexRule(s).
+func (c *IndicesConfig) derive(index *IndexConfig) []*IndexConfig {
+	var derived []*IndexConfig
+	for _, suffix := range index.Companions {
+		if suffix = strings.TrimSpace(suffix); suffix != "" {
+			derived = append(derived, &IndexConfig{Name: index.Name + suffix})
+		}
+	}
+
+	for _, rule := range c.DerivedIndices {
+		if rule.Matches(index.Name) {
+			derived = append(derived, rule.Index(index.Name))
+		}
+	}
+	return derived
+}
+
+// DerivedIndexFunc returns the configurations of the companion indices to create for the provided index, which allows
+// callers to register their own index naming conventions. See WithDerivedIndexFunc.
+type DerivedIndexFunc func(index *IndexConfig) []*IndexConfig
+
+// DerivedIndexRule declares a companion index named with Suffix appended to the name of each index in the
+// IndicesConfig that starts with Prefix, where an empty Prefix matches all indices. Settings, mappings, aliases, and
+// the ISM policy are applied to each companion index.
+//
+// Companion indices are no longer created implicitly. The upload index previously created for each index starting
+// with storage.IndexPrefixMetadataStorage is declared with a rule using the values of
+// storage.IndexPrefixMetadataStorage and storage.NamespaceFragmentUpload:
+//
+//	derived_indices:
+//	  - prefix: <storage.IndexPrefixMetadataStorage>
+//	    suffix: <storage.NamespaceFragmentUpload>
+//
+// or, without changing the indices configuration, with the equivalent DerivedIndexFunc:
+//
+//	WithIndicesDerivedIndexFunc(func(index *IndexConfig) []*IndexConfig {
+//		if strings.HasPrefix(index.Name, storage.IndexPrefixMetadataStorage) {
+//			return []*IndexConfig{{Name: index.Name + storage.NamespaceFragmentUpload}}
+//		}
+//		return nil
+//	})
+type DerivedIndexRule struct {
+	Prefix   string                    `json:"prefix,omitempty"`
+	Suffix   string                    `json:"suffix"`
+	Settings map[string]any            `json:"settings,omitempty"`
+	Mappings map[string]any            `json:"mappings,omitempty"`
+	Aliases  map[string]map[string]any `json:"aliases,omitempty"`
+	Policy   string                    `json:"policy,omitempty"`
+}
+
+// Matches returns whether the DerivedIndexRule applies to the index with the provided name.
+func (r *DerivedIndexRule) Matches(index string) bool {
+	return strings.TrimSpace(r.Suffix) != "" && strings.HasPrefix(index, r.Prefix)
+}
+
+// Index returns the configuration of the companion index for the index with the provided name.
+func (r *DerivedIndexRule) Index(index string) *IndexConfig {
+	return &IndexConfig{
+		Name:     index + strings.TrimSpace(r.Suffix),
+		Settings: r.Settings,
+		Mappings: r.Mappings,
+		Aliases:  r.Aliases,
+		Policy:   r.Policy,
+	}
+}
+
+// DataStreamConfig is the configuration of a data stream, which may be declared as the bare data stream name.
+type DataStreamConfig struct {
+	Name   string `json:"name"`
+	Policy string `json:"policy,omitempty"`
+}
+
+// UnmarshalJSON decodes the DataStreamConfig from either the data stream name or an object.
+func (c *DataStreamConfig) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &c.Name); err == nil {
+		return nil
+	}
+
+	type dataStreamConfig DataStreamConfig
+	var dc dataStreamConfig
+	if err := json.Unmarshal(b, &dc); err != nil {
+		return err
+	}
+	*c = DataStreamConfig(dc)
+	return nil
+}
+
+// IndexConfig is the configuration of an index, which may be declared as the bare index name. Settings, mappings, and
+// aliases are used when the index is created, and are merged with those from any matching index template. If Policy
+// is set, the ISM policy with that ID is attached to the index after it is created. Companions are the suffixes of
+// companion indices created alongside the index, which are named with the suffix appended to the index name.
+type IndexConfig struct {
+	Name       string                    `json:"name"`
+	Settings   map[string]any            `json:"settings,omitempty"`
+	Mappings   map[string]any            `json:"mappings,omitempty"`
+	Aliases    map[string]map[string]any `json:"aliases,omitempty"`
+	Policy     string                    `json:"policy,omitempty"`
+	Companions []string                  `json:"companions,omitempty"`
+}
+
+// UnmarshalJSON decodes the IndexConfig from either the index name or an object.
+func (c *IndexConfig) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &c.Name); err == nil {
+		return nil
+	}
+
+	type indexConfig IndexConfig
+	var ic indexConfig
+	if err := json.Unmarshal(b, &ic); err != nil {
+		return err
+	}
+	*c = IndexConfig(ic)
+	return nil
+}
+
+// AliasNames returns the sorted names of the aliases for the index.
+func (c *IndexConfig) AliasNames() []string {
+	return sortedKeys(c.Aliases)
+}
+
+// Options returns the options for creating the index.
+func (c *IndexConfig) Options() []func(*IndexOption) {
+	var options []func(*IndexOption)
+	if len(c.Settings) > 0 {
+		options = append(options, WithIndexSettings(c.Settings))
+	}
+
+	if len(c.Mappings) > 0 {
+		options = append(options, WithIndexMappings(c.Mappings))
+	}
+
+	for alias, properties := range c.Aliases {
+		options = append(options, WithIndexAlias(alias, properties))
+	}
+	return options
+}
+
+// IndexChange describes the change applied to the cluster for a single index or data stream, or the change that
+// would be applied in dry-run mode.
+type IndexChange struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Action      string   `json:"action"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Policy      string   `json:"policy,omitempty"`
+	DerivedFrom string   `json:"derived_from,omitempty"`
+}
+
+// IndicesReport is a container for the changes resulting from bootstrapping data streams and indices.
+type IndicesReport struct {
+	DryRun  bool           `json:"dry_run"`
+	Changes []*IndexChange `json:"changes,omitempty"`
+}
+
+// Created returns the changes that created an index or data stream.
+func (r *IndicesReport) Created() []*IndexChange {
+	var created []*IndexChange
+	for _, c := range r.Changes {
+		if c.Action == TemplateActionCreate {
+			created = append(created, c)
+		}
+	}
+	return created
+}
+
+// String returns a string representation of the IndicesReport.
+func (r *IndicesReport) String() string {
+	return string(anchor.ToJSONFormatted(r))
+}
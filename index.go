@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/transientvariable/repository-opensearch-go/bandaid"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// IndexService provides operations for managing the lifecycle, settings, and mappings of OpenSearch indices.
+//
+// Errors returned by the cluster are of type *ResponseError, and can be checked for missing or already existing
+// indices using errors.Is with ErrNotFound and ErrExists, respectively.
+type IndexService struct {
+	client *opensearch.Client
+}
+
+// Indices returns the IndexService for the Repository.
+func (r *Repository) Indices() *IndexService {
+	return &IndexService{client: r.client}
+}
+
+// Create creates the index using the provided options.
+func (s *IndexService) Create(ctx context.Context, index string, options ...func(*IndexOption)) error {
+	index = strings.TrimSpace(index)
+	if index == "" {
+		return ErrMalformedIndex
+	}
+
+	opts := &IndexOption{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	log.Debug(fmt.Sprintf("[opensearch] creating index with options:\n%s", opts), log.String("name", index))
+
+	request := opensearchapi.IndicesCreateRequest{Index: index}
+	if body := opts.Body(); body != nil {
+		request.Body = bytes.NewReader(anchor.ToJSON(body))
+	}
+	return do(ctx, s.client, "create index", request, nil)
+}
+
+// Delete deletes the provided indices.
+func (s *IndexService) Delete(ctx context.Context, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("[opensearch] deleting indices", log.String("names", strings.Join(names, ",")))
+
+	return do(ctx, s.client, "delete index", opensearchapi.IndicesDeleteRequest{Index: names}, nil)
+}
+
+// Exists returns whether all the provided indices, aliases, or data streams exist.
+func (s *IndexService) Exists(ctx context.Context, indices ...string) (bool, error) {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return false, err
+	}
+
+	response, err := opensearchapi.IndicesExistsRequest{Index: names}.Do(ctx, s.client)
+	if err != nil {
+		return false, fmt.Errorf("opensearch: error executing index exists request: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		if Body != nil {
+			if err := Body.Close(); err != nil {
+				log.Error("[opensearch]", log.Err(err))
+			}
+		}
+	}(response.Body)
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, responseError("index exists", response)
+}
+
+// Open opens the provided closed indices.
+func (s *IndexService) Open(ctx context.Context, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+	return do(ctx, s.client, "open index", opensearchapi.IndicesOpenRequest{Index: names}, nil)
+}
+
+// Close closes the provided indices, blocking read and write operations until they are reopened.
+func (s *IndexService) Close(ctx context.Context, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+	return do(ctx, s.client, "close index", opensearchapi.IndicesCloseRequest{Index: names}, nil)
+}
+
+// Settings returns the settings for the provided indices keyed by concrete index name. The settings of each index are
+// nested under `index` (e.g. {"index": {"number_of_replicas": "1"}}), which is the form accepted by PutSettings.
+func (s *IndexService) Settings(ctx context.Context, indices ...string) (map[string]map[string]any, error) {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return nil, err
+	}
+
+	var e map[string]struct {
+		Settings map[string]any `json:"settings"`
+	}
+	if err := do(ctx, s.client, "get index settings", opensearchapi.IndicesGetSettingsRequest{Index: names}, &e); err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]map[string]any, len(e))
+	for index, v := range e {
+		settings[index] = v.Settings
+	}
+	return settings, nil
+}
+
+// PutSettings updates the dynamic settings for the provided indices. The settings are sent as provided, so they are
+// either nested under `index` as returned by Settings (e.g. {"index": {"number_of_replicas": 1}}), or use dotted keys
+// (e.g. {"index.number_of_replicas": 1}).
+func (s *IndexService) PutSettings(ctx context.Context, settings map[string]any, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+
+	if len(settings) == 0 {
+		return ErrInvalid
+	}
+
+	return do(ctx, s.client, "put index settings", opensearchapi.IndicesPutSettingsRequest{
+		Index: names,
+		Body:  bytes.NewReader(anchor.ToJSON(settings)),
+	}, nil)
+}
+
+// Mapping returns the mappings for the provided indices keyed by concrete index name.
+func (s *IndexService) Mapping(ctx context.Context, indices ...string) (map[string]map[string]any, error) {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return nil, err
+	}
+
+	var e map[string]struct {
+		Mappings map[string]any `json:"mappings"`
+	}
+	if err := do(ctx, s.client, "get index mapping", opensearchapi.IndicesGetMappingRequest{Index: names}, &e); err != nil {
+		return nil, err
+	}
+
+	mappings := make(map[string]map[string]any, len(e))
+	for index, v := range e {
+		mappings[index] = v.Mappings
+	}
+	return mappings, nil
+}
+
+// PutMapping adds new fields to the mappings (e.g. `properties`) for the provided indices, or changes the mapping
+// parameters of existing fields that support being updated.
+func (s *IndexService) PutMapping(ctx context.Context, mapping map[string]any, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+
+	if len(mapping) == 0 {
+		return ErrInvalid
+	}
+
+	return do(ctx, s.client, "put index mapping", opensearchapi.IndicesPutMappingRequest{
+		Index: names,
+		Body:  bytes.NewReader(anchor.ToJSON(mapping)),
+	}, nil)
+}
+
+// Refresh makes all operations performed on the provided indices since the last refresh available for search.
+func (s *IndexService) Refresh(ctx context.Context, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+	return do(ctx, s.client, "refresh index", opensearchapi.IndicesRefreshRequest{Index: names}, nil)
+}
+
+// Flush persists the data in the transaction log of the provided indices to the Lucene index.
+func (s *IndexService) Flush(ctx context.Context, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+	return do(ctx, s.client, "flush index", opensearchapi.IndicesFlushRequest{Index: names}, nil)
+}
+
+// ForceMerge merges the segments of the provided indices. If maxNumSegments is greater than zero, the segments of each
+// shard are merged down to that number, otherwise the cluster determines whether merging is required.
+func (s *IndexService) ForceMerge(ctx context.Context, maxNumSegments int, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+
+	request := opensearchapi.IndicesForcemergeRequest{Index: names}
+	if maxNumSegments > 0 {
+		request.MaxNumSegments = &maxNumSegments
+	}
+	return do(ctx, s.client, "force merge index", request, nil)
+}
+
+// ForceMergeAsync starts merging the segments of the provided indices, and returns a Task that can be used for
+// tracking the progress of the operation. See ForceMerge.
+func (s *IndexService) ForceMergeAsync(ctx context.Context, maxNumSegments int, indices ...string) (*Task, error) {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return nil, err
+	}
+
+	waitForCompletion := false
+	request := bandaid.IndicesForcemergeRequest{
+		Index:             names,
+		WaitForCompletion: &waitForCompletion,
+	}
+
+	if maxNumSegments > 0 {
+		request.MaxNumSegments = &maxNumSegments
+	}
+	return (&TaskService{client: s.client}).submit(ctx, "force merge index", request)
+}
+
+// indexNames trims the provided index names, returning ErrMalformedIndex if no non-empty names are present.
+func indexNames(indices ...string) ([]string, error) {
+	var names []string
+	for _, i := range indices {
+		if i = strings.TrimSpace(i); i != "" {
+			names = append(names, i)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, ErrMalformedIndex
+	}
+	return names, nil
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+	"github.com/transientvariable/repository-opensearch-go/bandaid"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+
+	json "github.com/json-iterator/go"
+)
+
+// Policy represents an Index State Management (ISM) policy.
+type Policy struct {
+	ID          string          `json:"_id"`
+	SeqNo       int             `json:"_seq_no"`
+	PrimaryTerm int             `json:"_primary_term"`
+	Content     json.RawMessage `json:"policy"`
+}
+
+// String returns a string representation of the Policy.
+func (p *Policy) String() string {
+	return string(anchor.ToJSONFormatted(p))
+}
+
+// PolicyExplanation represents the ISM state of a managed index.
+type PolicyExplanation struct {
+	Index    string         `json:"index"`
+	PolicyID string         `json:"policy_id"`
+	Enabled  bool           `json:"enabled"`
+	State    string         `json:"state"`
+	Action   string         `json:"action"`
+	Info     map[string]any `json:"info,omitempty"`
+}
+
+// String returns a string representation of the PolicyExplanation.
+func (e *PolicyExplanation) String() string {
+	return string(anchor.ToJSONFormatted(e))
+}
+
+// ISMService provides operations for managing Index State Management (ISM) policies and the indices they are attached
+// to.
+type ISMService struct {
+	client *opensearch.Client
+}
+
+// ISM returns the ISMService for the Repository.
+func (r *Repository) ISM() *ISMService {
+	return &ISMService{client: r.client}
+}
+
+// Policy retrieves the ISM policy with the provided ID. If the policy does not exist, an error wrapping ErrNotFound is
+// returned.
+func (s *ISMService) Policy(ctx context.Context, id string) (*Policy, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, ErrInvalid
+	}
+
+	var policy Policy
+	if err := do(ctx, s.client, "get ism policy", bandaid.ISMGetPolicyRequest{PolicyID: id}, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// PutPolicy creates the ISM policy with the provided ID, where content is the JSON policy definition (the value of the
+// `policy` field). If current is not nil, the existing policy is updated using optimistic concurrency control.
+func (s *ISMService) PutPolicy(ctx context.Context, id string, content json.RawMessage, current *Policy) (*Policy, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || len(content) == 0 {
+		return nil, ErrInvalid
+	}
+
+	request := bandaid.ISMPutPolicyRequest{
+		PolicyID: id,
+		Body:     bytes.NewReader(anchor.ToJSON(map[string]json.RawMessage{"policy": content})),
+	}
+
+	if current != nil {
+		request.IfSeqNo = &current.SeqNo
+		request.IfPrimaryTerm = &current.PrimaryTerm
+	}
+
+	var policy Policy
+	if err := do(ctx, s.client, "put ism policy", request, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// DeletePolicy deletes the ISM policy with the provided ID.
+func (s *ISMService) DeletePolicy(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrInvalid
+	}
+	return do(ctx, s.client, "delete ism policy", bandaid.ISMDeletePolicyRequest{PolicyID: id}, nil)
+}
+
+// Attach attaches the ISM policy with the provided ID to the indices.
+func (s *ISMService) Attach(ctx context.Context, policyID string, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+
+	if policyID = strings.TrimSpace(policyID); policyID == "" {
+		return ErrInvalid
+	}
+
+	log.Debug("[opensearch] attaching ism policy",
+		log.String("policy_id", policyID),
+		log.String("indices", strings.Join(names, ",")))
+
+	return s.update(ctx, "add ism policy", bandaid.ISMAddPolicyRequest{
+		Index: names,
+		Body:  bytes.NewReader(anchor.ToJSON(map[string]string{"policy_id": policyID})),
+	})
+}
+
+// Detach detaches any ISM policy from the indices.
+func (s *ISMService) Detach(ctx context.Context, indices ...string) error {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("[opensearch] detaching ism policy", log.String("indices", strings.Join(names, ",")))
+
+	return s.update(ctx, "remove ism policy", bandaid.ISMRemovePolicyRequest{Index: names})
+}
+
+// Explain returns the ISM state for each of the provided indices keyed by index name. Indices that are not managed by
+// a policy have an empty PolicyID.
+func (s *ISMService) Explain(ctx context.Context, indices ...string) (map[string]*PolicyExplanation, error) {
+	names, err := indexNames(indices...)
+	if err != nil {
+		return nil, err
+	}
+
+	var e map[string]json.RawMessage
+	if err := do(ctx, s.client, "explain ism policy", bandaid.ISMExplainRequest{Index: names}, &e); err != nil {
+		return nil, err
+	}
+
+	explanations := make(map[string]*PolicyExplanation)
+	for index, raw := range e {
+		if index == "total_managed_indices" {
+			continue
+		}
+
+		var v struct {
+			Index    string `json:"index"`
+			PolicyID string `json:"policy_id"`
+			Enabled  *bool  `json:"enabled"`
+			State    struct {
+				Name string `json:"name"`
+			} `json:"state"`
+			Action struct {
+				Name string `json:"name"`
+			} `json:"action"`
+			Info map[string]any `json:"info"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode ism explanation for index %s: %w", index, err)
+		}
+
+		explanations[index] = &PolicyExplanation{
+			Index:    index,
+			PolicyID: v.PolicyID,
+			Enabled:  v.Enabled != nil && *v.Enabled,
+			State:    v.State.Name,
+			Action:   v.Action.Name,
+			Info:     v.Info,
+		}
+	}
+	return explanations, nil
+}
+
+func (s *ISMService) update(ctx context.Context, operation string, request opensearchapi.Request) error {
+	var e struct {
+		Failures      bool `json:"failures"`
+		FailedIndices []struct {
+			IndexName string `json:"index_name"`
+			Reason    string `json:"reason"`
+		} `json:"failed_indices"`
+	}
+	if err := do(ctx, s.client, operation, request, &e); err != nil {
+		return err
+	}
+
+	if e.Failures {
+		var reasons []string
+		for _, f := range e.FailedIndices {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", f.IndexName, f.Reason))
+		}
+		return fmt.Errorf("opensearch: %s failed for indices: %s", operation, strings.Join(reasons, "; "))
+	}
+	return nil
+}
+
+// applyPolicies creates the ISM policies read from the provided templates, where the policy ID is the template name,
+// or updates them if the installed policy differs from the template content. In dry-run mode, the changes are only
+// recorded in the report.
+func applyPolicies(ctx context.Context, client *opensearch.Client, report *TemplateReport, templates ...*Template) error {
+	ism := &ISMService{client: client}
+	for _, template := range templates {
+		var t struct {
+			Policy json.RawMessage `json:"policy"`
+		}
+		if err := json.Unmarshal(template.content, &t); err != nil || len(t.Policy) == 0 {
+			return fmt.Errorf("opensearch: ism policy %s is missing the policy field: %w", template.Path(), ErrInvalid)
+		}
+
+		current, err := ism.Policy(ctx, template.Name())
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		change := newTemplateChange(TemplateTypePolicy, template, 0, current != nil)
+		if current != nil {
+			change.Action = TemplateActionNone
+			if !policyContains(current.Content, t.Policy) {
+				change.Action = TemplateActionUpdate
+			}
+		}
+		report.Changes = append(report.Changes, change)
+
+		log.Info(fmt.Sprintf("[opensearch] %s %s", TemplateTypePolicy, change.Action),
+			log.String("name", template.Name()),
+			log.String("path", template.Path()),
+			log.Bool("dry_run", report.DryRun))
+
+		if report.DryRun || change.Action == TemplateActionNone {
+			continue
+		}
+
+		if _, err := ism.PutPolicy(ctx, template.Name(), t.Policy, current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// policyContains returns whether every field declared in the desired policy has the same value in the installed
+// policy. Fields added by the cluster (e.g. `policy_id`, `last_updated_time`, `schema_version`, and action defaults
+// such as `retry`) are ignored.
+func policyContains(installed json.RawMessage, desired json.RawMessage) bool {
+	var i, d any
+	if json.Unmarshal(installed, &i) != nil || json.Unmarshal(desired, &d) != nil {
+		return false
+	}
+	return contains(i, d)
+}
+
+// contains returns whether the installed value is a superset of the desired value, where maps must contain all the
+// desired keys, slices must have the same length with each element containing the desired element, and all other
+// values must be equal.
+func contains(installed any, desired any) bool {
+	switch d := desired.(type) {
+	case map[string]any:
+		i, ok := installed.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		for k, v := range d {
+			if !contains(i[k], v) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		i, ok := installed.([]any)
+		if !ok || len(i) != len(d) {
+			return false
+		}
+
+		for n := range d {
+			if !contains(i[n], d[n]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(installed, desired)
+}
@@ -0,0 +1,722 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+
+	json "github.com/json-iterator/go"
+)
+
+const (
+	MigrationDirectionUp   = "up"
+	MigrationDirectionDown = "down"
+
+	MigrationStepCreateIndex    = "create_index"
+	MigrationStepDeleteIndex    = "delete_index"
+	MigrationStepPutMapping     = "put_mapping"
+	MigrationStepPutSettings    = "put_settings"
+	MigrationStepPutPipeline    = "put_pipeline"
+	MigrationStepDeletePipeline = "delete_pipeline"
+	MigrationStepReindex        = "reindex"
+	MigrationStepUpdateAliases  = "update_aliases"
+	MigrationStepTemplates      = "templates"
+	MigrationStepIndices        = "indices"
+
+	migrationDocTypeLock      = "lock"
+	migrationDocTypeMigration = "migration"
+	migrationLockID           = "_lock"
+	migrationPageSize         = 1_000
+)
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)[_-](.+)\.json$`)
+
+// MigrationStep defines a single schema change performed by a Migration.
+//
+// The fields used depend on the step type:
+//   - create_index: Index, and optionally Body with `settings`, `mappings`, and `aliases`
+//   - delete_index: Index
+//   - put_mapping: Index and Body with the mapping (e.g. `properties`)
+//   - put_settings: Index and Body with the dynamic index settings nested under `index`, see IndexService.PutSettings
+//   - put_pipeline: ID and Body with the pipeline definition
+//   - delete_pipeline: ID
+//   - reindex: Source and Dest
+//   - update_aliases: Body with the `actions` for the `_aliases` API
+//   - templates: Path to a directory containing `ism`, `ecs`, and `index` template directories
+//   - indices: Path to an indices configuration file
+//
+// Paths are resolved relative to the directory containing the migration file.
+type MigrationStep struct {
+	Type   string          `json:"type"`
+	Index  string          `json:"index,omitempty"`
+	ID     string          `json:"id,omitempty"`
+	Source string          `json:"source,omitempty"`
+	Dest   string          `json:"dest,omitempty"`
+	Path   string          `json:"path,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Migration defines a versioned set of schema changes read from a file named `<version>_<name>.json`.
+type Migration struct {
+	Version     int             `json:"version"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Up          []MigrationStep `json:"up"`
+	Down        []MigrationStep `json:"down,omitempty"`
+	checksum    string
+	path        string
+}
+
+// Checksum returns the SHA-256 checksum of the migration file content.
+func (m *Migration) Checksum() string {
+	return m.checksum
+}
+
+// Path returns the path of the migration file.
+func (m *Migration) Path() string {
+	return m.path
+}
+
+// Reversible returns whether the Migration defines down steps.
+func (m *Migration) Reversible() bool {
+	return len(m.Down) > 0
+}
+
+// String returns a string representation of the Migration.
+func (m *Migration) String() string {
+	return string(anchor.ToJSONFormatted(m))
+}
+
+// MigrationRecord represents a migration that has been applied to the cluster.
+type MigrationRecord struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Checksum  string    `json:"checksum"`
+	AppliedAt time.Time `json:"applied_at"`
+	AppliedBy string    `json:"applied_by"`
+}
+
+// MigrationResult represents the result of executing a single Migration.
+type MigrationResult struct {
+	Version   int           `json:"version"`
+	Name      string        `json:"name"`
+	Direction string        `json:"direction"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// MigrationReport is a container for the results of applying migrations.
+type MigrationReport struct {
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Results []*MigrationResult `json:"results,omitempty"`
+
+	// Interrupted is the migration that was cut off because the migration lock was lost while it was executing.
+	Interrupted *MigrationResult `json:"interrupted,omitempty"`
+}
+
+// String returns a string representation of the MigrationReport.
+func (r *MigrationReport) String() string {
+	return string(anchor.ToJSONFormatted(r))
+}
+
+// ReadMigrations reads the migrations from the `*.json` files in the provided directory, ordered by version.
+func ReadMigrations(path string) ([]*Migration, error) {
+	path = strings.TrimSpace(path)
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*Migration
+	versions := make(map[int]string)
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("opensearch: migration file name %s must match <version>_<name>.json: %w",
+				e.Name(), ErrInvalid)
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if p, ok := versions[version]; ok {
+			return nil, fmt.Errorf("opensearch: duplicate migration version %d: %s, %s: %w", version, p, e.Name(), ErrInvalid)
+		}
+		versions[version] = e.Name()
+
+		p := filepath.Join(path, e.Name())
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		m := &Migration{}
+		if err := json.Unmarshal(content, m); err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode migration %s: %w", p, err)
+		}
+
+		sum := sha256.Sum256(content)
+		m.Version = version
+		m.Name = match[2]
+		m.checksum = hex.EncodeToString(sum[:])
+		m.path = p
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrate applies the migrations read from the provided directory using ReadMigrations.
+//
+// Applied migrations are recorded in a dedicated index, and a lock document in the same index ensures that only one
+// instance migrates at a time. The lock is renewed while migrations are applied, and is only released by the instance
+// holding it. If the lock is lost while migrations are applied, the returned error wraps ErrMigrationLockLost and
+// MigrationReport.Interrupted is the migration that was cut off.
+//
+// Migrations that were previously applied must not be modified; if the checksum of an applied migration differs from
+// the file on disk, ErrMigrationChecksum is returned.
+func (r *Repository) Migrate(ctx context.Context, path string, options ...func(*MigrationOption)) (*MigrationReport, error) {
+	migrations, err := ReadMigrations(path)
+	if err != nil {
+		return nil, err
+	}
+	return migrate(ctx, r, migrations, newMigrationOption(options...))
+}
+
+// MigrationStatus returns the migrations that have been applied, ordered by version.
+func (r *Repository) MigrationStatus(ctx context.Context, options ...func(*MigrationOption)) ([]*MigrationRecord, error) {
+	opts := newMigrationOption(options...)
+	exists, err := r.Indices().Exists(ctx, opts.index)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return (&migrator{repository: r, options: opts}).applied(ctx)
+}
+
+func migrate(ctx context.Context, r *Repository, migrations []*Migration, opts *MigrationOption) (*MigrationReport, error) {
+	m := &migrator{repository: r, options: opts}
+
+	log.Info(fmt.Sprintf("[opensearch] applying migrations with options:\n%s", opts))
+
+	if err := createIndex(ctx, r.Indices(), opts.index, WithIndexMappings(map[string]any{
+		"dynamic": false,
+		"properties": map[string]any{
+			"type":        map[string]any{"type": "keyword"},
+			"version":     map[string]any{"type": "long"},
+			"name":        map[string]any{"type": "keyword"},
+			"checksum":    map[string]any{"type": "keyword"},
+			"applied_at":  map[string]any{"type": "date"},
+			"applied_by":  map[string]any{"type": "keyword"},
+			"owner":       map[string]any{"type": "keyword"},
+			"acquired_at": map[string]any{"type": "date"},
+			"expires_at":  map[string]any{"type": "date"},
+		},
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		m.renew(ctx, cancel)
+	}()
+	defer func() {
+		cancel(nil)
+		<-renewed
+		if err := m.unlock(context.Background()); err != nil {
+			log.Error("[opensearch] could not release migration lock", log.Err(err))
+		}
+	}()
+
+	records, err := m.applied(ctx)
+	if err != nil {
+		return nil, lockLost(ctx, err)
+	}
+
+	applied := make(map[int]*MigrationRecord)
+	report := &MigrationReport{}
+
+	// interrupted returns the error of a migration that failed, recording the migration in the report if the failure
+	// was caused by losing the migration lock
+	interrupted := func(mig *Migration, direction string, start time.Time, err error) error {
+		if errors.Is(context.Cause(ctx), ErrMigrationLockLost) {
+			report.Interrupted = &MigrationResult{
+				Version:   mig.Version,
+				Name:      mig.Name,
+				Direction: direction,
+				Duration:  time.Since(start),
+			}
+		}
+		return lockLost(ctx, err)
+	}
+
+	for _, rec := range records {
+		applied[rec.Version] = rec
+		report.From = max(report.From, rec.Version)
+	}
+
+	byVersion := make(map[int]*Migration)
+	target := 0
+	for _, mig := range migrations {
+		byVersion[mig.Version] = mig
+		target = max(target, mig.Version)
+
+		if rec, ok := applied[mig.Version]; ok && rec.Checksum != mig.Checksum() {
+			return nil, fmt.Errorf("opensearch: migration %d (%s): %w", mig.Version, mig.Path(), ErrMigrationChecksum)
+		}
+	}
+
+	if opts.target != nil {
+		target = *opts.target
+	}
+	report.To = report.From
+
+	// down: revert applied migrations above the target in descending order
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if rec.Version <= target {
+			break
+		}
+
+		mig, ok := byVersion[rec.Version]
+		if !ok {
+			return report, fmt.Errorf("opensearch: applied migration %d (%s) not found: %w", rec.Version, rec.Name, ErrNotFound)
+		}
+
+		if !mig.Reversible() {
+			return report, fmt.Errorf("opensearch: migration %d (%s): %w", mig.Version, mig.Name, ErrMigrationIrreversible)
+		}
+
+		start := time.Now()
+		result, err := m.execute(ctx, mig, MigrationDirectionDown)
+		if err != nil {
+			return report, interrupted(mig, MigrationDirectionDown, start, err)
+		}
+		report.Results = append(report.Results, result)
+		report.To = 0
+		if i > 0 {
+			report.To = records[i-1].Version
+		}
+	}
+
+	// up: apply pending migrations up to the target in ascending order
+	for _, mig := range migrations {
+		if mig.Version > target {
+			break
+		}
+
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		start := time.Now()
+		result, err := m.execute(ctx, mig, MigrationDirectionUp)
+		if err != nil {
+			return report, interrupted(mig, MigrationDirectionUp, start, err)
+		}
+		report.Results = append(report.Results, result)
+		report.To = max(report.To, mig.Version)
+	}
+
+	log.Info(fmt.Sprintf("[opensearch] completed migrations:\n%s", report))
+
+	return report, nil
+}
+
+type migrator struct {
+	acquiredAt      time.Time
+	lockPrimaryTerm int
+	lockSeqNo       int
+	options         *MigrationOption
+	repository      *Repository
+}
+
+// migrationLock is the lock document, along with the sequence number and primary term of its last change.
+type migrationLock struct {
+	SeqNo       int `json:"_seq_no"`
+	PrimaryTerm int `json:"_primary_term"`
+	Source      struct {
+		Owner     string    `json:"owner"`
+		ExpiresAt time.Time `json:"expires_at"`
+	} `json:"_source"`
+}
+
+func (m *migrator) client() *opensearch.Client {
+	return m.repository.client
+}
+
+func (m *migrator) execute(ctx context.Context, mig *Migration, direction string) (*MigrationResult, error) {
+	log.Info("[opensearch] executing migration",
+		log.Int("version", mig.Version),
+		log.String("name", mig.Name),
+		log.String("direction", direction))
+
+	start := time.Now()
+	steps := mig.Up
+	if direction == MigrationDirectionDown {
+		steps = mig.Down
+	}
+
+	for i, step := range steps {
+		if err := m.step(ctx, filepath.Dir(mig.Path()), step); err != nil {
+			return nil, fmt.Errorf("opensearch: migration %d (%s) %s step %d (%s) failed: %w",
+				mig.Version, mig.Name, direction, i+1, step.Type, err)
+		}
+	}
+
+	var err error
+	if direction == MigrationDirectionUp {
+		err = do(ctx, m.client(), "record migration", opensearchapi.IndexRequest{
+			Index:      m.options.index,
+			DocumentID: strconv.Itoa(mig.Version),
+			Body: bytes.NewReader(anchor.ToJSON(map[string]any{
+				"type":       migrationDocTypeMigration,
+				"version":    mig.Version,
+				"name":       mig.Name,
+				"checksum":   mig.Checksum(),
+				"applied_at": time.Now().UTC(),
+				"applied_by": m.options.owner,
+			})),
+			Refresh: "true",
+		}, nil)
+	} else {
+		err = do(ctx, m.client(), "remove migration record", opensearchapi.DeleteRequest{
+			Index:      m.options.index,
+			DocumentID: strconv.Itoa(mig.Version),
+			Refresh:    "true",
+		}, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &MigrationResult{
+		Version:   mig.Version,
+		Name:      mig.Name,
+		Direction: direction,
+		Duration:  time.Since(start),
+	}, nil
+}
+
+func (m *migrator) step(ctx context.Context, dir string, step MigrationStep) error {
+	var body map[string]any
+	if len(step.Body) > 0 {
+		if err := json.Unmarshal(step.Body, &body); err != nil {
+			return fmt.Errorf("could not decode step body: %w", err)
+		}
+	}
+
+	indices := m.repository.Indices()
+	switch strings.ToLower(strings.TrimSpace(step.Type)) {
+	case MigrationStepCreateIndex:
+		var options []func(*IndexOption)
+		if settings, ok := body["settings"].(map[string]any); ok {
+			options = append(options, WithIndexSettings(settings))
+		}
+
+		if mappings, ok := body["mappings"].(map[string]any); ok {
+			options = append(options, WithIndexMappings(mappings))
+		}
+
+		if aliases, ok := body["aliases"].(map[string]any); ok {
+			for a, p := range aliases {
+				properties, _ := p.(map[string]any)
+				options = append(options, WithIndexAlias(a, properties))
+			}
+		}
+		return createIndex(ctx, indices, step.Index, options...)
+	case MigrationStepDeleteIndex:
+		err := indices.Delete(ctx, step.Index)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	case MigrationStepPutMapping:
+		return indices.PutMapping(ctx, body, step.Index)
+	case MigrationStepPutSettings:
+		return indices.PutSettings(ctx, body, step.Index)
+	case MigrationStepPutPipeline:
+		if strings.TrimSpace(step.ID) == "" || len(step.Body) == 0 {
+			return ErrInvalid
+		}
+		return do(ctx, m.client(), "put pipeline", opensearchapi.IngestPutPipelineRequest{
+			PipelineID: step.ID,
+			Body:       bytes.NewReader(step.Body),
+		}, nil)
+	case MigrationStepDeletePipeline:
+		if strings.TrimSpace(step.ID) == "" {
+			return ErrInvalid
+		}
+
+		err := do(ctx, m.client(), "delete pipeline", opensearchapi.IngestDeletePipelineRequest{PipelineID: step.ID}, nil)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	case MigrationStepReindex:
+		_, err := m.repository.Reindex(ctx, step.Source, step.Dest, WithReindexRefresh(true))
+		return err
+	case MigrationStepUpdateAliases:
+		if len(step.Body) == 0 {
+			return ErrInvalid
+		}
+		return do(ctx, m.client(), "update aliases", opensearchapi.IndicesUpdateAliasesRequest{
+			Body: bytes.NewReader(step.Body),
+		}, nil)
+	case MigrationStepTemplates:
+		_, err := prepareTemplates(ctx, m.client(), dirFS(resolvePath(dir, step.Path)), ".")
+		return err
+	case MigrationStepIndices:
+		p := resolvePath(dir, step.Path)
+		_, err := prepareIndices(ctx, m.client(), dirFS(filepath.Dir(p)), filepath.Base(p))
+		return err
+	}
+	return fmt.Errorf("unsupported migration step type %s: %w", step.Type, ErrInvalid)
+}
+
+// applied returns the migration records ordered by version, paging through them using search_after so that the
+// number of records is not limited by the max result window of the index.
+func (m *migrator) applied(ctx context.Context) ([]*MigrationRecord, error) {
+	var (
+		records     []*MigrationRecord
+		searchAfter []any
+	)
+
+	for {
+		query := map[string]any{
+			"query": map[string]any{"term": map[string]any{"type": migrationDocTypeMigration}},
+			"sort":  []any{map[string]any{"version": SortDirectionAsc}},
+			"size":  migrationPageSize,
+		}
+
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		var e struct {
+			Hits struct {
+				Hits []struct {
+					ID     string          `json:"_id"`
+					Source json.RawMessage `json:"_source"`
+					Sort   []any           `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		err := do(ctx, m.client(), "get migration records", opensearchapi.SearchRequest{
+			Index: []string{m.options.index},
+			Body:  bytes.NewReader(anchor.ToJSON(query)),
+		}, &e)
+		if err != nil {
+			return nil, err
+		}
+
+		hits := e.Hits.Hits
+		for _, hit := range hits {
+			rec := &MigrationRecord{}
+			if err := json.Unmarshal(hit.Source, rec); err != nil {
+				return nil, fmt.Errorf("opensearch: could not decode migration record %s: %w", hit.ID, err)
+			}
+			records = append(records, rec)
+		}
+
+		if len(hits) < migrationPageSize {
+			return records, nil
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+}
+
+func (m *migrator) lock(ctx context.Context) error {
+	now := time.Now().UTC()
+	m.acquiredAt = now
+
+	var v migrationLock
+	err := do(ctx, m.client(), "acquire migration lock", opensearchapi.IndexRequest{
+		Index:      m.options.index,
+		DocumentID: migrationLockID,
+		Body:       bytes.NewReader(m.lockDocument(now.Add(m.options.lockTTL))),
+		OpType:     "create",
+		Refresh:    "true",
+	}, &v)
+
+	if err == nil {
+		m.lockSeqNo, m.lockPrimaryTerm = v.SeqNo, v.PrimaryTerm
+		return nil
+	}
+
+	var re *ResponseError
+	if !errors.As(err, &re) || re.StatusCode != http.StatusConflict {
+		return err
+	}
+
+	var e migrationLock
+	err = do(ctx, m.client(), "get migration lock", opensearchapi.GetRequest{
+		Index:      m.options.index,
+		DocumentID: migrationLockID,
+	}, &e)
+	if err != nil {
+		return err
+	}
+
+	if now.Before(e.Source.ExpiresAt) {
+		return fmt.Errorf("opensearch: %w: %s (expires %s)", ErrMigrationLocked, e.Source.Owner,
+			e.Source.ExpiresAt.Format(time.RFC3339))
+	}
+
+	log.Warn("[opensearch] taking over expired migration lock",
+		log.String("owner", e.Source.Owner),
+		log.Time("expired_at", e.Source.ExpiresAt))
+
+	err = do(ctx, m.client(), "acquire migration lock", opensearchapi.IndexRequest{
+		Index:         m.options.index,
+		DocumentID:    migrationLockID,
+		Body:          bytes.NewReader(m.lockDocument(now.Add(m.options.lockTTL))),
+		IfSeqNo:       &e.SeqNo,
+		IfPrimaryTerm: &e.PrimaryTerm,
+		Refresh:       "true",
+	}, &v)
+	if errors.As(err, &re) && re.StatusCode == http.StatusConflict {
+		return fmt.Errorf("opensearch: %w", ErrMigrationLocked)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	m.lockSeqNo, m.lockPrimaryTerm = v.SeqNo, v.PrimaryTerm
+	return nil
+}
+
+// renew extends the expiry of the migration lock at a third of the lock TTL until the context is done, so that the
+// lock is not taken over by another instance while migrations are applied. If the lock is no longer held, the context
+// is canceled with an error wrapping ErrMigrationLockLost.
+func (m *migrator) renew(ctx context.Context, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(m.options.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		// the renewal is not canceled along with the migration so that the sequence number of a renewal that has been
+		// applied is always recorded for releasing the lock
+		var v migrationLock
+		err := do(context.WithoutCancel(ctx), m.client(), "renew migration lock", opensearchapi.IndexRequest{
+			Index:         m.options.index,
+			DocumentID:    migrationLockID,
+			Body:          bytes.NewReader(m.lockDocument(time.Now().UTC().Add(m.options.lockTTL))),
+			IfSeqNo:       &m.lockSeqNo,
+			IfPrimaryTerm: &m.lockPrimaryTerm,
+			Refresh:       "true",
+		}, &v)
+
+		var re *ResponseError
+		if errors.As(err, &re) && (re.StatusCode == http.StatusConflict || re.StatusCode == http.StatusNotFound) {
+			log.Error("[opensearch] migration lock is no longer held", log.Err(err))
+			cancel(fmt.Errorf("opensearch: %w", ErrMigrationLockLost))
+			return
+		}
+
+		if err != nil {
+			log.Warn("[opensearch] could not renew migration lock", log.Err(err))
+			continue
+		}
+		m.lockSeqNo, m.lockPrimaryTerm = v.SeqNo, v.PrimaryTerm
+	}
+}
+
+// unlock deletes the migration lock if it is still held, i.e. it is owned by the migrator and has not changed since it
+// was last acquired or renewed. Otherwise, an error wrapping ErrMigrationLockLost is returned.
+func (m *migrator) unlock(ctx context.Context) error {
+	var e migrationLock
+	err := do(ctx, m.client(), "get migration lock", opensearchapi.GetRequest{
+		Index:      m.options.index,
+		DocumentID: migrationLockID,
+	}, &e)
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("opensearch: %w", ErrMigrationLockLost)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if e.Source.Owner != m.options.owner || e.SeqNo != m.lockSeqNo || e.PrimaryTerm != m.lockPrimaryTerm {
+		return fmt.Errorf("opensearch: %w: held by %s", ErrMigrationLockLost, e.Source.Owner)
+	}
+
+	err = do(ctx, m.client(), "release migration lock", opensearchapi.DeleteRequest{
+		Index:         m.options.index,
+		DocumentID:    migrationLockID,
+		IfSeqNo:       &m.lockSeqNo,
+		IfPrimaryTerm: &m.lockPrimaryTerm,
+		Refresh:       "true",
+	}, nil)
+
+	var re *ResponseError
+	if errors.As(err, &re) && re.StatusCode == http.StatusConflict {
+		return fmt.Errorf("opensearch: %w", ErrMigrationLockLost)
+	}
+	return err
+}
+
+// lockLost returns the provided error joined with the cause of the context if the context was canceled because the
+// migration lock was lost, and the provided error otherwise.
+func lockLost(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrMigrationLockLost) {
+		return errors.Join(cause, err)
+	}
+	return err
+}
+
+// lockDocument returns the migration lock document owned by the migrator that expires at the provided time.
+func (m *migrator) lockDocument(expiresAt time.Time) []byte {
+	return anchor.ToJSON(map[string]any{
+		"type":        migrationDocTypeLock,
+		"owner":       m.options.owner,
+		"acquired_at": m.acquiredAt,
+		"expires_at":  expiresAt,
+	})
+}
+
+func resolvePath(dir string, path string) string {
+	path = strings.TrimSpace(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -5,15 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
-	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/transientvariable/cadre/storage"
+	"github.com/transientvariable/anchor"
 	"github.com/transientvariable/log-go"
 	"github.com/transientvariable/repository-opensearch-go/bandaid"
 
@@ -48,21 +49,75 @@ func New(options ...func(*Option)) *Repository {
 
 		client := NewClient(options...)
 		if opts.mappingCreate {
-			if err := prepareTemplates(client, opts.mappingTemplatePath); err != nil {
+			templateFS, templateDir := opts.mappingFS, opts.mappingTemplatePath
+			indicesFS, indicesPath := opts.mappingFS, opts.mappingIndicesPath
+			if opts.mappingFS == nil {
+				templateFS, templateDir = dirFS(opts.mappingTemplatePath), "."
+				indicesFS, indicesPath = dirFS(filepath.Dir(opts.mappingIndicesPath)), filepath.Base(opts.mappingIndicesPath)
+			}
+
+			report, err := prepareTemplates(context.Background(), client, templateFS, templateDir,
+				WithTemplateDryRun(opts.mappingDryRun))
+			if err != nil {
 				log.Fatal("[opensearch] could not prepare templates", log.Err(err))
 			}
 
-			if err := prepareIndices(client, opts.mappingIndicesPath); err != nil {
+			log.Info(fmt.Sprintf("[opensearch] template report:\n%s", report))
+
+			indicesOptions := []func(*IndicesOption){WithIndicesDryRun(opts.mappingDryRun)}
+			for _, fn := range opts.derivedIndices {
+				indicesOptions = append(indicesOptions, WithDerivedIndexFunc(fn))
+			}
+
+			indicesReport, err := prepareIndices(context.Background(), client, indicesFS, indicesPath, indicesOptions...)
+			if err != nil {
 				log.Fatal("[opensearch] could not prepare indices", log.Err(err))
 			}
+
+			log.Info(fmt.Sprintf("[opensearch] indices report:\n%s", indicesReport))
 		}
 		repository = &Repository{
 			client: client,
 		}
+
+		if opts.migrationPath != "" {
+			if _, err := repository.Migrate(context.Background(), opts.migrationPath); err != nil {
+				log.Fatal("[opensearch] could not apply migrations", log.Err(err))
+			}
+		}
 	})
 	return repository
 }
 
+// ApplyTemplates creates or updates the ISM policies, ingest pipelines, component templates, index templates, and
+// legacy templates read from the `ism`, `pipeline`, `ecs`, `index`, and `legacy` subdirectories of the provided path,
+// and returns a report of the changes. Installed templates and pipelines are only updated if the on-disk file has a
+// newer `version`, where the pipeline ID is the file name.
+//
+// Component templates are applied before the index templates that reference them. If an index template references a
+// component template that is neither on disk nor installed, no templates are applied and an error wrapping
+// ErrNotFound is returned.
+func (r *Repository) ApplyTemplates(ctx context.Context, path string, options ...func(*TemplateOption)) (*TemplateReport, error) {
+	return prepareTemplates(ctx, r.client, dirFS(path), ".", options...)
+}
+
+// ApplyTemplatesFS is the same as ApplyTemplates, but reads the templates from the provided directory of fsys.
+func (r *Repository) ApplyTemplatesFS(ctx context.Context, fsys fs.FS, dir string, options ...func(*TemplateOption)) (*TemplateReport, error) {
+	return prepareTemplates(ctx, r.client, fsys, dir, options...)
+}
+
+// ApplyIndices creates the data streams, indices, and derived companion indices declared in the JSON or YAML indices
+// configuration file at the provided path that do not exist, attaches their ISM policies, and returns a report of the
+// changes. Existing indices and data streams are left unchanged. See IndicesConfig.
+func (r *Repository) ApplyIndices(ctx context.Context, path string, options ...func(*IndicesOption)) (*IndicesReport, error) {
+	return prepareIndices(ctx, r.client, dirFS(filepath.Dir(path)), filepath.Base(path), options...)
+}
+
+// ApplyIndicesFS is the same as ApplyIndices, but reads the indices configuration file from fsys.
+func (r *Repository) ApplyIndicesFS(ctx context.Context, fsys fs.FS, name string, options ...func(*IndicesOption)) (*IndicesReport, error) {
+	return prepareIndices(ctx, r.client, fsys, name, options...)
+}
+
 // Close releases any resources held by the OpenSearch Repository.
 func (r *Repository) Close() error {
 	return nil
@@ -119,6 +174,56 @@ func (r *Repository) execute(ctx context.Context, request opensearchapi.Request)
 	}
 }
 
+// do executes the request using the provided client and decodes the response body into v if it is not nil. If the
+// cluster responds with an error, a *ResponseError is returned.
+func do(ctx context.Context, client *opensearch.Client, operation string, request opensearchapi.Request, v any) error {
+	response, err := request.Do(ctx, client)
+	if err != nil {
+		return fmt.Errorf("opensearch: error executing %s request: %w", operation, err)
+	}
+	defer func(Body io.ReadCloser) {
+		if err := Body.Close(); err != nil {
+			log.Error("[opensearch]", log.Err(err))
+		}
+	}(response.Body)
+
+	if response.IsError() {
+		return responseError(operation, response)
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+			return fmt.Errorf("opensearch: could not decode %s response body: %w", operation, err)
+		}
+	}
+	return nil
+}
+
+// responseError decodes the error envelope from the body of an unsuccessful response.
+func responseError(operation string, response *opensearchapi.Response) *ResponseError {
+	e := &ResponseError{
+		Operation:  operation,
+		StatusCode: response.StatusCode,
+	}
+
+	var em map[string]any
+	if response.Body == nil || json.NewDecoder(response.Body).Decode(&em) != nil {
+		e.Reason = response.Status()
+		return e
+	}
+
+	switch v := em["error"].(type) {
+	case map[string]any:
+		e.Type, _ = v["type"].(string)
+		e.Reason, _ = v["reason"].(string)
+	case string:
+		e.Reason = v
+	default:
+		e.Reason = string(anchor.ToJSON(em))
+	}
+	return e
+}
+
 func (r *Repository) logQueryError(err error) error {
 	if err != nil {
 		log.Error("[opensearch] query execution error", log.Err(err))
@@ -126,167 +231,332 @@ func (r *Repository) logQueryError(err error) error {
 	return err
 }
 
-func prepareTemplates(client *opensearch.Client, templatePath string) error {
-	ecsTemplates, err := ReadTemplates(filepath.Join(templatePath, TemplateDirNameECS))
-	if err != nil {
-		return err
+func prepareTemplates(ctx context.Context, client *opensearch.Client, fsys fs.FS, dir string, options ...func(*TemplateOption)) (*TemplateReport, error) {
+	opts := &TemplateOption{}
+	for _, opt := range options {
+		opt(opts)
 	}
 
-	err = applyComponentTemplates(client, ecsTemplates...)
+	report := &TemplateReport{DryRun: opts.dryRun}
+
+	ismDir := path.Join(dir, TemplateDirNameISM)
+	if _, err := fs.Stat(fsys, ismDir); err == nil {
+		policies, err := ReadTemplatesFS(fsys, ismDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := applyPolicies(ctx, client, report, policies...); err != nil {
+			return nil, err
+		}
+	}
+
+	ecsTemplates, err := ReadTemplatesFS(fsys, path.Join(dir, TemplateDirNameECS))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	indexTemplates, err := ReadTemplates(filepath.Join(templatePath, TemplateDirNameIndex))
+	indexTemplates, err := ReadTemplatesFS(fsys, path.Join(dir, TemplateDirNameIndex))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = applyIndexTemplates(client, indexTemplates...)
+	templates := append(ecsTemplates, indexTemplates...)
+	for _, d := range []string{TemplateDirNamePipeline, TemplateDirNameLegacy} {
+		if _, err := fs.Stat(fsys, path.Join(dir, d)); err != nil {
+			continue
+		}
+
+		t, err := ReadTemplatesFS(fsys, path.Join(dir, d))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t...)
+	}
+
+	templates, err = orderTemplates(templates...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	if err := validateComponents(ctx, client, templates...); err != nil {
+		return nil, err
+	}
+
+	if err := applyTemplates(ctx, client, report, templates...); err != nil {
+		return nil, err
+	}
+	return report, nil
 }
 
-func applyComponentTemplates(client *opensearch.Client, templates ...*Template) error {
+// applyTemplates creates each of the provided templates that is not installed, and updates those whose version is
+// newer than the installed version. In dry-run mode, the changes are only recorded in the report.
+func applyTemplates(ctx context.Context, client *opensearch.Client, report *TemplateReport, templates ...*Template) error {
 	for _, template := range templates {
-		templateExistsResponse, err := client.Cluster.ExistsComponentTemplate(template.Name())
+		templateType := template.Type()
+		installedVersion, installed, err := templateVersion(ctx, client, templateType, template.Name())
 		if err != nil {
 			return err
 		}
 
-		if templateExistsResponse.StatusCode != http.StatusOK {
-			log.Info("[opensearch] applying component template",
-				log.String("name", template.Name()),
-				log.String("path", template.Path()))
+		change := newTemplateChange(templateType, template, installedVersion, installed)
+		report.Changes = append(report.Changes, change)
 
-			putComponentTemplateResponse, err := client.Cluster.PutComponentTemplate(template.Name(), template.Reader())
-			if err != nil {
-				return err
-			}
+		log.Info(fmt.Sprintf("[opensearch] %s %s", templateType, change.Action),
+			log.String("name", template.Name()),
+			log.String("path", template.Path()),
+			log.Int("version", change.Version),
+			log.Int("installed_version", change.InstalledVersion),
+			log.Bool("dry_run", report.DryRun))
 
-			if putComponentTemplateResponse.IsError() {
-				return errors.New(putComponentTemplateResponse.String())
-			}
-		} else {
-			log.Info("[opensearch] component template exists, skipping creation",
-				log.String("name", template.Name()),
-				log.String("path", template.Path()))
+		if report.DryRun || change.Action == TemplateActionNone {
+			continue
+		}
+
+		var request opensearchapi.Request
+		switch templateType {
+		case TemplateTypeComponent:
+			request = opensearchapi.ClusterPutComponentTemplateRequest{Name: template.Name(), Body: template.Reader()}
+		case TemplateTypeIndex:
+			request = opensearchapi.IndicesPutIndexTemplateRequest{Name: template.Name(), Body: template.Reader()}
+		case TemplateTypeLegacy:
+			request = opensearchapi.IndicesPutTemplateRequest{Name: template.Name(), Body: template.Reader()}
+		case TemplateTypePipeline:
+			request = opensearchapi.IngestPutPipelineRequest{PipelineID: template.Name(), Body: template.Reader()}
+		default:
+			return fmt.Errorf("opensearch: unsupported template type %s: %w", templateType, ErrInvalid)
+		}
+
+		if err := do(ctx, client, "put "+templateType, request, nil); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func applyIndexTemplates(client *opensearch.Client, templates ...*Template) error {
-	for _, template := range templates {
-		templateExistsResponse, err := client.Indices.ExistsIndexTemplate(template.Name())
-		if err != nil {
-			return err
+// validateComponents verifies that every component template referenced by the provided index templates is either one
+// of the provided component templates or installed in the cluster.
+func validateComponents(ctx context.Context, client *opensearch.Client, templates ...*Template) error {
+	components := make(map[string]bool)
+	for _, t := range templates {
+		if t.Type() == TemplateTypeComponent {
+			components[t.Name()] = true
 		}
+	}
 
-		if templateExistsResponse.StatusCode != http.StatusOK {
-			log.Info("[opensearch] applying index template",
-				log.String("name", template.Name()),
-				log.String("path", template.Path()))
+	var missing []string
+	for _, t := range templates {
+		if t.Type() != TemplateTypeIndex {
+			continue
+		}
 
-			putIndexTemplateResponse, err := client.Indices.PutIndexTemplate(template.Name(), template.Reader())
-			if err != nil {
-				return err
+		for _, name := range t.ComposedOf() {
+			exists, checked := components[name]
+			if !checked {
+				_, installed, err := templateVersion(ctx, client, TemplateTypeComponent, name)
+				if err != nil {
+					return err
+				}
+				components[name], exists = installed, installed
 			}
 
-			if putIndexTemplateResponse.IsError() {
-				return errors.New(putIndexTemplateResponse.String())
+			if !exists {
+				missing = append(missing, fmt.Sprintf("%s (referenced by %s)", name, t.Name()))
 			}
-		} else {
-			log.Info("[opensearch] index template exists, skipping creation",
-				log.String("name", template.Name()),
-				log.String("path", template.Path()))
 		}
 	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("opensearch: missing component templates: %s: %w", strings.Join(missing, ", "), ErrNotFound)
+	}
 	return nil
 }
 
-func prepareIndices(client *opensearch.Client, indexMappingPath string) error {
-	type indicesConfig struct {
-		DataStreams []string `json:"data_streams"`
-		Indices     []string `json:"indices"`
+// templateVersion returns the version of the installed template with the provided type and name, and whether it is
+// installed.
+func templateVersion(ctx context.Context, client *opensearch.Client, templateType string, name string) (int, bool, error) {
+	var (
+		request opensearchapi.Request
+		e       struct {
+			ComponentTemplates []struct {
+				ComponentTemplate struct {
+					Version int `json:"version"`
+				} `json:"component_template"`
+			} `json:"component_templates"`
+			IndexTemplates []struct {
+				IndexTemplate struct {
+					Version int `json:"version"`
+				} `json:"index_template"`
+			} `json:"index_templates"`
+		}
+
+		// keyed holds the response for legacy templates and ingest pipelines, which are keyed by name.
+		keyed map[string]struct {
+			Version int `json:"version"`
+		}
+	)
+
+	var v any = &e
+	switch templateType {
+	case TemplateTypeComponent:
+		request = opensearchapi.ClusterGetComponentTemplateRequest{Name: []string{name}}
+	case TemplateTypeIndex:
+		request = opensearchapi.IndicesGetIndexTemplateRequest{Name: []string{name}}
+	case TemplateTypeLegacy:
+		request, v = opensearchapi.IndicesGetTemplateRequest{Name: []string{name}}, &keyed
+	case TemplateTypePipeline:
+		request, v = opensearchapi.IngestGetPipelineRequest{PipelineID: name}, &keyed
+	default:
+		return 0, false, fmt.Errorf("opensearch: unsupported template type %s: %w", templateType, ErrInvalid)
 	}
 
-	d, err := os.Open(indexMappingPath)
+	if err := do(ctx, client, "get "+templateType, request, v); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	if t, ok := keyed[name]; ok {
+		return t.Version, true, nil
+	}
+
+	if len(e.ComponentTemplates) > 0 {
+		return e.ComponentTemplates[0].ComponentTemplate.Version, true, nil
+	}
+
+	if len(e.IndexTemplates) > 0 {
+		return e.IndexTemplates[0].IndexTemplate.Version, true, nil
+	}
+	return 0, false, nil
+}
+
+// prepareIndices creates the data streams, indices, and derived companion indices declared in the named JSON or YAML
+// indices configuration file of fsys that do not exist, attaches their ISM policies, and returns a report of the
+// changes. In dry-run mode, the changes are only recorded in the report.
+func prepareIndices(ctx context.Context, client *opensearch.Client, fsys fs.FS, name string, options ...func(*IndicesOption)) (*IndicesReport, error) {
+	opts := &IndicesOption{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	b, err := readFile(fsys, name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var indices indicesConfig
-	if err = json.NewDecoder(d).Decode(&indices); err != nil {
-		return err
+	var config IndicesConfig
+	if err = json.Unmarshal(b, &config); err != nil {
+		return nil, fmt.Errorf("opensearch: could not decode indices configuration %s: %w", name, err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	report := &IndicesReport{DryRun: opts.dryRun}
+	dataStreams := &DataStreamService{client: client}
+	ism := &ISMService{client: client}
+	for _, ds := range config.DataStreams {
+		ds.Name = strings.TrimSpace(ds.Name)
+		if ds.Name == "" {
+			return nil, fmt.Errorf("opensearch: data stream name is required in %s: %w", name, ErrInvalid)
+		}
 
-	for _, ds := range indices.DataStreams {
-		getDataStreamRequest := bandaid.IndicesGetDataStreamRequest{Name: []string{ds}}
-		getDataStreamResponse, err := getDataStreamRequest.Do(ctx, client)
+		exists, err := dataStreams.Exists(ctx, ds.Name)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if getDataStreamResponse.StatusCode != http.StatusOK {
-			log.Debug("[opensearch] creating data stream", log.String("name", ds))
+		change := newIndexChange(report, IndexTypeDataStream, ds.Name, ds.Policy, exists)
+		if report.DryRun || exists {
+			continue
+		}
 
-			createDataStreamRequest := bandaid.IndicesCreateDataStreamRequest{Name: ds}
-			createDataStreamResponse, err := createDataStreamRequest.Do(ctx, client)
-			if err != nil {
-				return err
-			}
+		if err := dataStreams.Create(ctx, ds.Name); err != nil {
+			return nil, err
+		}
 
-			if createDataStreamResponse.IsError() {
-				return errors.New(createDataStreamResponse.String())
+		if change.Policy != "" {
+			if err := ism.Attach(ctx, change.Policy, ds.Name); err != nil {
+				return nil, err
 			}
-		} else {
-			log.Debug("[opensearch] data stream exists, skipping creation", log.String("name", ds))
 		}
 	}
 
-	for _, index := range indices.Indices {
-		err = createIndex(client, index)
-		if err != nil {
-			return err
+	indices := &IndexService{client: client}
+	for _, index := range config.Indices {
+		index.Name = strings.TrimSpace(index.Name)
+		if index.Name == "" {
+			return nil, fmt.Errorf("opensearch: index name is required in %s: %w", name, ErrInvalid)
+		}
+
+		configs := append([]*IndexConfig{index}, config.derive(index)...)
+		for _, fn := range opts.derived {
+			configs = append(configs, fn(index)...)
 		}
 
-		if strings.HasPrefix(index, storage.IndexPrefixMetadataStorage) {
-			err = createIndex(client, index+storage.NamespaceFragmentUpload)
+		for i, c := range configs {
+			if c == nil || strings.TrimSpace(c.Name) == "" {
+				return nil, fmt.Errorf("opensearch: derived index name is required for %s: %w", index.Name, ErrInvalid)
+			}
+
+			exists, err := indices.Exists(ctx, c.Name)
 			if err != nil {
-				return err
+				return nil, err
+			}
+
+			change := newIndexChange(report, IndexTypeIndex, c.Name, c.Policy, exists)
+			change.Aliases = c.AliasNames()
+			if i > 0 {
+				change.DerivedFrom = index.Name
+			}
+			if report.DryRun || exists {
+				continue
+			}
+
+			if err := indices.Create(ctx, c.Name, c.Options()...); err != nil {
+				return nil, err
+			}
+
+			if change.Policy != "" {
+				if err := ism.Attach(ctx, change.Policy, c.Name); err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
-	return nil
+	return report, nil
 }
 
-func createIndex(client *opensearch.Client, index string) error {
-	existsResponse, err := client.Indices.Exists([]string{index})
-	if err != nil {
-		return err
+// newIndexChange records the change for the index or data stream with the provided name in the report.
+func newIndexChange(report *IndicesReport, indexType string, name string, policy string, exists bool) *IndexChange {
+	change := &IndexChange{
+		Name:   name,
+		Type:   indexType,
+		Action: TemplateActionCreate,
+		Policy: strings.TrimSpace(policy),
 	}
 
-	if existsResponse.StatusCode != http.StatusOK {
-		log.Debug("[opensearch] creating index", log.String("name", index))
+	if exists {
+		change.Action = TemplateActionNone
+	}
+	report.Changes = append(report.Changes, change)
 
-		createIndexResponse, err := client.Indices.Create(index)
-		if err != nil {
-			return err
-		}
+	log.Info(fmt.Sprintf("[opensearch] %s %s", indexType, change.Action),
+		log.String("name", name),
+		log.String("policy", change.Policy),
+		log.Bool("dry_run", report.DryRun))
+	return change
+}
 
-		if createIndexResponse.IsError() {
-			return errors.New(createIndexResponse.String())
-		}
-	} else {
+func createIndex(ctx context.Context, indices *IndexService, index string, options ...func(*IndexOption)) error {
+	exists, err := indices.Exists(ctx, index)
+	if err != nil {
+		return err
+	}
+
+	if exists {
 		log.Debug("[opensearch] index exists, skipping creation", log.String("name", index))
+		return nil
 	}
-	return nil
+	return indices.Create(ctx, index, options...)
 }
 
 func copyAny(src []any) []any {
@@ -298,6 +568,14 @@ func copyAny(src []any) []any {
 	return nil
 }
 
+func copyBool(src *bool) *bool {
+	if src != nil {
+		dst := *src
+		return &dst
+	}
+	return nil
+}
+
 func copyStrs(src []string) []string {
 	if len(src) > 0 {
 		dst := make([]string, len(src))
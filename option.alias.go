@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/transientvariable/anchor"
+)
+
+const (
+	AliasActionAdd         = "add"
+	AliasActionRemove      = "remove"
+	AliasActionRemoveIndex = "remove_index"
+)
+
+// AliasAction defines a single action performed as part of an atomic alias update.
+type AliasAction struct {
+	action        string
+	alias         string
+	filter        map[string]any
+	index         string
+	indexRouting  string
+	isWriteIndex  *bool
+	routing       string
+	searchRouting string
+}
+
+// AddAlias creates an AliasAction for adding the alias to the index using the provided options.
+func AddAlias(index string, alias string, options ...func(*AliasAction)) *AliasAction {
+	a := &AliasAction{
+		action: AliasActionAdd,
+		alias:  strings.TrimSpace(alias),
+		index:  strings.TrimSpace(index),
+	}
+	for _, opt := range options {
+		opt(a)
+	}
+	return a
+}
+
+// RemoveAlias creates an AliasAction for removing the alias from the index.
+func RemoveAlias(index string, alias string) *AliasAction {
+	return &AliasAction{
+		action: AliasActionRemove,
+		alias:  strings.TrimSpace(alias),
+		index:  strings.TrimSpace(index),
+	}
+}
+
+// RemoveIndex creates an AliasAction for deleting the index as part of an atomic alias update.
+func RemoveIndex(index string) *AliasAction {
+	return &AliasAction{
+		action: AliasActionRemoveIndex,
+		index:  strings.TrimSpace(index),
+	}
+}
+
+// Validate returns ErrInvalid if the AliasAction is missing its index or alias name.
+func (a *AliasAction) Validate() error {
+	if a.index == "" {
+		return ErrMalformedIndex
+	}
+
+	if a.action != AliasActionRemoveIndex && a.alias == "" {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding of the AliasAction in the format expected by the `_aliases` API.
+func (a *AliasAction) MarshalJSON() ([]byte, error) {
+	params := map[string]any{"index": a.index}
+	if a.alias != "" {
+		params["alias"] = a.alias
+	}
+
+	if len(a.filter) > 0 {
+		params["filter"] = a.filter
+	}
+
+	if a.indexRouting != "" {
+		params["index_routing"] = a.indexRouting
+	}
+
+	if a.isWriteIndex != nil {
+		params["is_write_index"] = *a.isWriteIndex
+	}
+
+	if a.routing != "" {
+		params["routing"] = a.routing
+	}
+
+	if a.searchRouting != "" {
+		params["search_routing"] = a.searchRouting
+	}
+	return anchor.ToJSON(map[string]any{a.action: params}), nil
+}
+
+// String returns a string representation of the AliasAction.
+func (a *AliasAction) String() string {
+	return string(anchor.ToJSONFormatted(a))
+}
+
+// WithAliasFilter sets the query used for limiting the documents that are visible through an alias.
+func WithAliasFilter(filter map[string]any) func(*AliasAction) {
+	return func(a *AliasAction) {
+		a.filter = filter
+	}
+}
+
+// WithAliasIndexRouting sets the routing value used for indexing operations performed through an alias.
+func WithAliasIndexRouting(routing string) func(*AliasAction) {
+	return func(a *AliasAction) {
+		a.indexRouting = strings.TrimSpace(routing)
+	}
+}
+
+// WithAliasRouting sets the routing value used for both indexing and search operations performed through an alias.
+func WithAliasRouting(routing string) func(*AliasAction) {
+	return func(a *AliasAction) {
+		a.routing = strings.TrimSpace(routing)
+	}
+}
+
+// WithAliasSearchRouting sets the routing value used for search operations performed through an alias.
+func WithAliasSearchRouting(routing string) func(*AliasAction) {
+	return func(a *AliasAction) {
+		a.searchRouting = strings.TrimSpace(routing)
+	}
+}
+
+// WithAliasWriteIndex sets whether the index is the write index for an alias that refers to multiple indices.
+func WithAliasWriteIndex(isWriteIndex bool) func(*AliasAction) {
+	return func(a *AliasAction) {
+		a.isWriteIndex = &isWriteIndex
+	}
+}
+
+// BlueGreenOption is a container for options used for configuring a blue/green index cutover.
+type BlueGreenOption struct {
+	deletePrevious  bool
+	indexOptions    []func(*IndexOption)
+	readAlias       string
+	reindexPrevious bool
+	version         string
+	writeAlias      string
+}
+
+// String returns a string representation of BlueGreenOption.
+func (o *BlueGreenOption) String() string {
+	options := make(map[string]any)
+	options["delete_previous"] = o.deletePrevious
+	options["read_alias"] = o.readAlias
+	options["reindex_previous"] = o.reindexPrevious
+	options["version"] = o.version
+	options["write_alias"] = o.writeAlias
+	return string(anchor.ToJSONFormatted(options))
+}
+
+// WithDeletePrevious sets whether the indices previously referenced by the read alias are deleted once the aliases
+// have been moved. Indices previously referenced only by the write alias are kept, and only the write alias is removed
+// from them. Default is false.
+func WithDeletePrevious(deletePrevious bool) func(*BlueGreenOption) {
+	return func(o *BlueGreenOption) {
+		o.deletePrevious = deletePrevious
+	}
+}
+
+// WithIndexVersion sets the version suffix used for naming the new index. If not set, the current UTC time formatted
+// as `20060102150405` is used.
+func WithIndexVersion(version string) func(*BlueGreenOption) {
+	return func(o *BlueGreenOption) {
+		o.version = strings.TrimSpace(version)
+	}
+}
+
+// WithNewIndexOptions sets the options used for creating the new index.
+func WithNewIndexOptions(options ...func(*IndexOption)) func(*BlueGreenOption) {
+	return func(o *BlueGreenOption) {
+		o.indexOptions = append(o.indexOptions, options...)
+	}
+}
+
+// WithReadAlias sets the alias used by readers that is moved to the new index.
+func WithReadAlias(alias string) func(*BlueGreenOption) {
+	return func(o *BlueGreenOption) {
+		o.readAlias = strings.TrimSpace(alias)
+	}
+}
+
+// WithReindexPrevious sets whether documents from the indices previously referenced by the read alias are reindexed
+// into the new index before the aliases are moved. Default is false.
+func WithReindexPrevious(reindex bool) func(*BlueGreenOption) {
+	return func(o *BlueGreenOption) {
+		o.reindexPrevious = reindex
+	}
+}
+
+// WithWriteAlias sets the alias used by writers that is moved to the new index.
+func WithWriteAlias(alias string) func(*BlueGreenOption) {
+	return func(o *BlueGreenOption) {
+		o.writeAlias = strings.TrimSpace(alias)
+	}
+}
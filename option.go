@@ -1,6 +1,9 @@
 package repository
 
-import "strings"
+import (
+	"io/fs"
+	"strings"
+)
 
 type Option struct {
 	addresses           []string
@@ -11,9 +14,13 @@ type Option struct {
 	retryMax            int
 	retryStatus         []string
 	bulkStatsEnable     bool
+	derivedIndices      []DerivedIndexFunc
 	mappingCreate       bool
+	mappingDryRun       bool
+	mappingFS           fs.FS
 	mappingTemplatePath string
 	mappingIndicesPath  string
+	migrationPath       string
 }
 
 func WithAddresses(addresses string) func(*Option) {
@@ -68,14 +75,51 @@ func WithMappingCreate(create bool) func(*Option) {
 	}
 }
 
+// WithMappingDryRun sets whether template and index bootstrapping only reports the templates and indices that would be
+// created, updated, or left unchanged without applying them.
+func WithMappingDryRun(dryRun bool) func(*Option) {
+	return func(o *Option) {
+		o.mappingDryRun = dryRun
+	}
+}
+
 func WithMappingTemplatePath(path string) func(*Option) {
 	return func(o *Option) {
 		o.mappingTemplatePath = path
 	}
 }
 
+// WithMappingFS sets the file system from which templates and the indices configuration are read, e.g. an embed.FS
+// with bundled assets. When set, the paths provided by WithMappingTemplatePath and WithIndicesPath are resolved
+// against fsys rather than the OS file system.
+func WithMappingFS(fsys fs.FS) func(*Option) {
+	return func(o *Option) {
+		o.mappingFS = fsys
+	}
+}
+
+// WithIndicesDerivedIndexFunc adds the DerivedIndexFunc used for declaring the companion indices of each index in
+// the indices configuration when bootstrapping indices. See WithIndicesPath and WithDerivedIndexFunc.
+func WithIndicesDerivedIndexFunc(fn DerivedIndexFunc) func(*Option) {
+	return func(o *Option) {
+		if fn != nil {
+			o.derivedIndices = append(o.derivedIndices, fn)
+		}
+	}
+}
+
+// WithIndicesPath sets the path of the JSON or YAML indices configuration file used for bootstrapping data streams and
+// indices. See IndicesConfig.
 func WithIndicesPath(path string) func(*Option) {
 	return func(o *Option) {
 		o.mappingIndicesPath = path
 	}
 }
+
+// WithMigrationPath sets the directory containing the migrations that are applied when the Repository is created. See
+// Repository.Migrate.
+func WithMigrationPath(path string) func(*Option) {
+	return func(o *Option) {
+		o.migrationPath = strings.TrimSpace(path)
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/transientvariable/anchor"
+)
+
+const (
+	SettingNumberOfShards   = "number_of_shards"
+	SettingNumberOfReplicas = "number_of_replicas"
+)
+
+// IndexOption is a container for options used for configuring an index.
+type IndexOption struct {
+	aliases  map[string]any
+	mappings map[string]any
+	settings map[string]any
+}
+
+// Body returns the request body for creating an index using the IndexOption, or nil if no settings, mappings, or
+// aliases have been set.
+func (o *IndexOption) Body() map[string]any {
+	body := make(map[string]any)
+	if len(o.aliases) > 0 {
+		body["aliases"] = o.aliases
+	}
+
+	if len(o.mappings) > 0 {
+		body["mappings"] = o.mappings
+	}
+
+	if len(o.settings) > 0 {
+		body["settings"] = o.settings
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+	return body
+}
+
+// String returns a string representation of IndexOption.
+func (o *IndexOption) String() string {
+	options := make(map[string]any)
+	options["aliases"] = o.aliases
+	options["mappings"] = o.mappings
+	options["settings"] = o.settings
+	return string(anchor.ToJSONFormatted(options))
+}
+
+// WithIndexAlias adds the alias to IndexOption for an index, where properties are the optional alias properties (e.g.
+// `filter`, `routing`, `is_write_index`).
+func WithIndexAlias(alias string, properties map[string]any) func(*IndexOption) {
+	return func(o *IndexOption) {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			if o.aliases == nil {
+				o.aliases = make(map[string]any)
+			}
+
+			if properties == nil {
+				properties = make(map[string]any)
+			}
+			o.aliases[alias] = properties
+		}
+	}
+}
+
+// WithIndexMappings sets the mappings (e.g. `properties`, `dynamic`) for an index.
+func WithIndexMappings(mappings map[string]any) func(*IndexOption) {
+	return func(o *IndexOption) {
+		if len(mappings) > 0 {
+			o.mappings = mappings
+		}
+	}
+}
+
+// WithIndexReplicas sets the number of replica shards for an index.
+func WithIndexReplicas(replicas int) func(*IndexOption) {
+	return func(o *IndexOption) {
+		if replicas >= 0 {
+			WithIndexSetting(SettingNumberOfReplicas, replicas)(o)
+		}
+	}
+}
+
+// WithIndexSetting adds the setting and its corresponding value to IndexOption for an index. If the setting name is
+// empty, or its corresponding value is nil, the setting will not be added.
+func WithIndexSetting(setting string, value any) func(*IndexOption) {
+	return func(o *IndexOption) {
+		if setting = strings.TrimSpace(setting); setting != "" && value != nil {
+			if o.settings == nil {
+				o.settings = make(map[string]any)
+			}
+			o.settings[setting] = value
+		}
+	}
+}
+
+// WithIndexSettings adds the provided map of settings to IndexOption for an index.
+func WithIndexSettings(settings map[string]any) func(*IndexOption) {
+	return func(o *IndexOption) {
+		for s, v := range settings {
+			WithIndexSetting(s, v)(o)
+		}
+	}
+}
+
+// WithIndexShards sets the number of primary shards for an index.
+func WithIndexShards(shards int) func(*IndexOption) {
+	return func(o *IndexOption) {
+		if shards > 0 {
+			WithIndexSetting(SettingNumberOfShards, shards)(o)
+		}
+	}
+}
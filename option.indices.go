@@ -0,0 +1,25 @@
+package repository
+
+// IndicesOption is a container for options used for bootstrapping data streams and indices.
+type IndicesOption struct {
+	derived []DerivedIndexFunc
+	dryRun  bool
+}
+
+// WithDerivedIndexFunc adds the DerivedIndexFunc used for declaring the companion indices of each index in the
+// IndicesConfig, in addition to those declared by the configuration itself.
+func WithDerivedIndexFunc(fn DerivedIndexFunc) func(*IndicesOption) {
+	return func(o *IndicesOption) {
+		if fn != nil {
+			o.derived = append(o.derived, fn)
+		}
+	}
+}
+
+// WithIndicesDryRun sets whether to only report the data streams and indices that would be created without creating
+// them. Default is false.
+func WithIndicesDryRun(dryRun bool) func(*IndicesOption) {
+	return func(o *IndicesOption) {
+		o.dryRun = dryRun
+	}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/transientvariable/anchor"
+)
+
+const (
+	// DefaultMigrationIndex is the default name of the index used for recording applied migrations.
+	DefaultMigrationIndex = "repository-migrations"
+
+	// DefaultMigrationLockTTL is the default period after which a migration lock held by another instance is considered
+	// abandoned.
+	DefaultMigrationLockTTL = 15 * time.Minute
+)
+
+// MigrationOption is a container for options used for applying migrations.
+type MigrationOption struct {
+	index   string
+	lockTTL time.Duration
+	owner   string
+	target  *int
+}
+
+// String returns a string representation of MigrationOption.
+func (o *MigrationOption) String() string {
+	options := make(map[string]any)
+	options["index"] = o.index
+	options["lock_ttl"] = o.lockTTL.String()
+	options["owner"] = o.owner
+	options["target"] = o.target
+	return string(anchor.ToJSONFormatted(options))
+}
+
+func newMigrationOption(options ...func(*MigrationOption)) *MigrationOption {
+	opts := &MigrationOption{
+		index:   DefaultMigrationIndex,
+		lockTTL: DefaultMigrationLockTTL,
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.owner == "" {
+		hostname, _ := os.Hostname()
+		opts.owner = fmt.Sprintf("%s:%d", hostname, os.Getpid())
+	}
+	return opts
+}
+
+// WithMigrationIndex sets the name of the index used for recording applied migrations and the migration lock.
+// Default is DefaultMigrationIndex.
+func WithMigrationIndex(index string) func(*MigrationOption) {
+	return func(o *MigrationOption) {
+		if index = strings.TrimSpace(index); index != "" {
+			o.index = index
+		}
+	}
+}
+
+// WithMigrationLockTTL sets the period after which a migration lock held by another instance is considered abandoned
+// and may be taken over. Default is DefaultMigrationLockTTL.
+func WithMigrationLockTTL(ttl time.Duration) func(*MigrationOption) {
+	return func(o *MigrationOption) {
+		if ttl > 0 {
+			o.lockTTL = ttl
+		}
+	}
+}
+
+// WithMigrationOwner sets the name identifying the instance holding the migration lock. Default is
+// `<hostname>:<pid>`.
+func WithMigrationOwner(owner string) func(*MigrationOption) {
+	return func(o *MigrationOption) {
+		o.owner = strings.TrimSpace(owner)
+	}
+}
+
+// WithMigrationTarget sets the version to migrate to. If the target is lower than the current version, the `down`
+// steps of the applied migrations above the target are executed in reverse order. By default, all pending migrations
+// are applied.
+func WithMigrationTarget(version int) func(*MigrationOption) {
+	return func(o *MigrationOption) {
+		if version >= 0 {
+			o.target = &version
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/transientvariable/anchor"
+)
+
+const (
+	ReindexConflictsAbort   = "abort"
+	ReindexConflictsProceed = "proceed"
+
+	ReindexOpTypeCreate = "create"
+	ReindexOpTypeIndex  = "index"
+
+	reindexSlicesAuto = "auto"
+)
+
+// ReindexRemote defines the configuration for reindexing documents from a remote cluster. The remote host must be
+// allowed by the `reindex.remote.whitelist` setting of the destination cluster.
+type ReindexRemote struct {
+	Host           string            `json:"host"`
+	Username       string            `json:"username,omitempty"`
+	Password       string            `json:"password,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	SocketTimeout  time.Duration     `json:"-"`
+	ConnectTimeout time.Duration     `json:"-"`
+}
+
+// ReindexOption is a container for options used for configuring a reindex operation.
+type ReindexOption struct {
+	batchSize         int
+	conflicts         string
+	maxDocs           int
+	opType            string
+	pipeline          string
+	refresh           bool
+	remote            *ReindexRemote
+	requestsPerSecond int
+	script            *Script
+	searchOptions     []func(*SearchOption)
+	slices            any
+	timeout           time.Duration
+}
+
+// String returns a string representation of ReindexOption.
+func (o *ReindexOption) String() string {
+	options := make(map[string]any)
+	options["batch_size"] = o.batchSize
+	options["conflicts"] = o.conflicts
+	options["max_docs"] = o.maxDocs
+	options["op_type"] = o.opType
+	options["pipeline"] = o.pipeline
+	options["refresh"] = o.refresh
+	options["requests_per_second"] = o.requestsPerSecond
+	options["script"] = o.script
+	options["slices"] = o.slices
+	options["timeout"] = o.timeout.String()
+	if o.remote != nil {
+		options["remote_host"] = o.remote.Host
+	}
+	return string(anchor.ToJSONFormatted(options))
+}
+
+// WithReindexBatchSize sets the number of documents retrieved from the source for each batch. Default is 1000.
+func WithReindexBatchSize(size int) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// WithReindexConflicts sets whether to abort or proceed when version conflicts are encountered, either
+// ReindexConflictsAbort or ReindexConflictsProceed. Any other value will be ignored.
+func WithReindexConflicts(conflicts string) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		conflicts = strings.ToLower(strings.TrimSpace(conflicts))
+		if conflicts == ReindexConflictsAbort || conflicts == ReindexConflictsProceed {
+			o.conflicts = conflicts
+		}
+	}
+}
+
+// WithReindexMaxDocs sets the maximum number of documents to reindex.
+func WithReindexMaxDocs(maxDocs int) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		if maxDocs > 0 {
+			o.maxDocs = maxDocs
+		}
+	}
+}
+
+// WithReindexOpType sets the operation used for writing documents to the destination index, either
+// ReindexOpTypeIndex or ReindexOpTypeCreate. Using ReindexOpTypeCreate only writes documents that are missing from the
+// destination. Any other value will be ignored.
+func WithReindexOpType(opType string) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		opType = strings.ToLower(strings.TrimSpace(opType))
+		if opType == ReindexOpTypeCreate || opType == ReindexOpTypeIndex {
+			o.opType = opType
+		}
+	}
+}
+
+// WithReindexPipeline sets the ingest pipeline used for processing documents written to the destination index.
+func WithReindexPipeline(pipeline string) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		o.pipeline = strings.TrimSpace(pipeline)
+	}
+}
+
+// WithReindexQuery sets the search options used for selecting and filtering the source documents. The query, source
+// include and exclude fields, and additional targets set using WithIndices are used.
+func WithReindexQuery(options ...func(*SearchOption)) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		o.searchOptions = append(o.searchOptions, options...)
+	}
+}
+
+// WithReindexRefresh sets whether the destination index is refreshed once the reindex operation completes.
+func WithReindexRefresh(refresh bool) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		o.refresh = refresh
+	}
+}
+
+// WithReindexRemote sets the remote cluster the source documents are retrieved from.
+func WithReindexRemote(remote ReindexRemote) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		if remote.Host = strings.TrimSpace(remote.Host); remote.Host != "" {
+			o.remote = &remote
+		}
+	}
+}
+
+// WithReindexRequestsPerSecond throttles the reindex operation to the provided number of sub-requests per second.
+func WithReindexRequestsPerSecond(requestsPerSecond int) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		if requestsPerSecond > 0 {
+			o.requestsPerSecond = requestsPerSecond
+		}
+	}
+}
+
+// WithReindexScript sets the script used for modifying documents while they are reindexed.
+func WithReindexScript(script Script) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		if script.Source = strings.TrimSpace(script.Source); script.Source != "" {
+			o.script = &script
+		}
+	}
+}
+
+// WithReindexSlices sets the number of slices the reindex operation is divided into for parallel execution.
+func WithReindexSlices(slices int) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		if slices > 0 {
+			o.slices = slices
+		}
+	}
+}
+
+// WithReindexSlicesAuto sets the number of slices the reindex operation is divided into to be chosen automatically,
+// typically one slice per shard.
+func WithReindexSlicesAuto() func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		o.slices = reindexSlicesAuto
+	}
+}
+
+// WithReindexTimeout sets the period each indexing request waits for unavailable shards.
+func WithReindexTimeout(timeout time.Duration) func(*ReindexOption) {
+	return func(o *ReindexOption) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
@@ -3,8 +3,11 @@ package repository
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/transientvariable/anchor"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
 )
 
 const (
@@ -15,6 +18,18 @@ const (
 	BoolPredicateMust    = "must"
 	BoolPredicateMustNot = "must_not"
 	BoolPredicateShould  = "should"
+
+	// SearchTypeQueryThenFetch scores documents using term and document frequencies local to each shard (default).
+	SearchTypeQueryThenFetch = "query_then_fetch"
+
+	// SearchTypeDFSQueryThenFetch scores documents using term and document frequencies gathered across all shards.
+	SearchTypeDFSQueryThenFetch = "dfs_query_then_fetch"
+
+	ExpandWildcardsAll    = "all"
+	ExpandWildcardsClosed = "closed"
+	ExpandWildcardsHidden = "hidden"
+	ExpandWildcardsNone   = "none"
+	ExpandWildcardsOpen   = "open"
 )
 
 var (
@@ -24,6 +39,19 @@ var (
 		BoolPredicateMustNot,
 		BoolPredicateShould,
 	}
+
+	expandWildcards = []string{
+		ExpandWildcardsAll,
+		ExpandWildcardsClosed,
+		ExpandWildcardsHidden,
+		ExpandWildcardsNone,
+		ExpandWildcardsOpen,
+	}
+
+	searchTypes = []string{
+		SearchTypeQueryThenFetch,
+		SearchTypeDFSQueryThenFetch,
+	}
 )
 
 type scriptedMetricAgg struct {
@@ -42,33 +70,52 @@ type BoolQuery struct {
 
 // SearchOption is a container for options used for configuring a search query.
 type SearchOption struct {
-	docvalueFields    bool
-	excludeFields     []string
-	includeFields     []string
-	matches           []BoolQuery
-	matchAll          bool
-	queryString       string
-	queryStringFields []string
-	searchAfter       []any
-	size              int
-	sort              []map[string]any
-	sourceEnabled     bool
-	sumField          string
-	sumKey            string
-	terms             []BoolQuery
+	allowNoIndices            *bool
+	allowPartialSearchResults *bool
+	docvalueFields            bool
+	excludeFields             []string
+	expandWildcards           []string
+	ignoreUnavailable         *bool
+	includeFields             []string
+	indices                   []string
+	matches                   []BoolQuery
+	matchAll                  bool
+	preference                string
+	queryString               string
+	queryStringFields         []string
+	requestCache              *bool
+	routing                   []string
+	searchAfter               []any
+	searchType                string
+	size                      int
+	sort                      []map[string]any
+	sourceEnabled             bool
+	sumField                  string
+	sumKey                    string
+	terminateAfter            int
+	terms                     []BoolQuery
+	timeout                   time.Duration
 }
 
 // Copy creates a deep copy of the SearchOption.
 func (o *SearchOption) Copy() *SearchOption {
 	options := &SearchOption{
-		docvalueFields: o.docvalueFields,
-		matchAll:       o.matchAll,
-		queryString:    o.queryString,
-		size:           o.size,
-		sort:           o.sort,
-		sourceEnabled:  o.sourceEnabled,
-		sumField:       o.sumField,
-		sumKey:         o.sumKey,
+		allowNoIndices:            copyBool(o.allowNoIndices),
+		allowPartialSearchResults: copyBool(o.allowPartialSearchResults),
+		docvalueFields:            o.docvalueFields,
+		ignoreUnavailable:         copyBool(o.ignoreUnavailable),
+		matchAll:                  o.matchAll,
+		preference:                o.preference,
+		queryString:               o.queryString,
+		requestCache:              copyBool(o.requestCache),
+		searchType:                o.searchType,
+		size:                      o.size,
+		sort:                      o.sort,
+		sourceEnabled:             o.sourceEnabled,
+		sumField:                  o.sumField,
+		sumKey:                    o.sumKey,
+		terminateAfter:            o.terminateAfter,
+		timeout:                   o.timeout,
 	}
 
 	excludeFields := copyStrs(o.excludeFields)
@@ -76,11 +123,21 @@ func (o *SearchOption) Copy() *SearchOption {
 		options.excludeFields = excludeFields
 	}
 
+	expandWildcards := copyStrs(o.expandWildcards)
+	if len(expandWildcards) > 0 {
+		options.expandWildcards = expandWildcards
+	}
+
 	includeFields := copyStrs(o.includeFields)
 	if len(includeFields) > 0 {
 		options.includeFields = includeFields
 	}
 
+	indices := copyStrs(o.indices)
+	if len(indices) > 0 {
+		options.indices = indices
+	}
+
 	var matches []BoolQuery
 	for _, m := range o.matches {
 		matches = append(matches, BoolQuery{
@@ -96,6 +153,11 @@ func (o *SearchOption) Copy() *SearchOption {
 		options.queryStringFields = queryStringFields
 	}
 
+	routing := copyStrs(o.routing)
+	if len(routing) > 0 {
+		options.routing = routing
+	}
+
 	searchAfter := copyAny(o.searchAfter)
 	if len(searchAfter) > 0 {
 		options.searchAfter = searchAfter
@@ -128,6 +190,20 @@ func (o *SearchOption) SearchAfter() []any {
 	return nil
 }
 
+// Targets returns the de-duplicated list of index, alias, or data stream names for a query, combining the
+// comma-separated names in the provided index with any names added using WithIndices.
+func (o *SearchOption) Targets(index string) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	for _, t := range append(strings.Split(index, ","), o.indices...) {
+		if t = strings.TrimSpace(t); t != "" && !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
 // Sum returns whether to sum a specific field for matching documents.
 func (o *SearchOption) Sum() bool {
 	return o.sumField != "" && o.sumKey != ""
@@ -141,22 +217,49 @@ func (o *SearchOption) SumKey() string {
 // String returns a string representation of SearchOption.
 func (o *SearchOption) String() string {
 	options := make(map[string]any)
+	options["allow_no_indices"] = o.allowNoIndices
+	options["allow_partial_search_results"] = o.allowPartialSearchResults
 	options["exclude_fields"] = o.excludeFields
+	options["expand_wildcards"] = o.expandWildcards
+	options["ignore_unavailable"] = o.ignoreUnavailable
 	options["include_fields"] = o.includeFields
+	options["indices"] = o.indices
 	options["matches"] = o.matches
 	options["match_all"] = o.matchAll
+	options["preference"] = o.preference
 	options["query_string"] = o.queryString
 	options["query_string_fields"] = o.queryStringFields
+	options["request_cache"] = o.requestCache
+	options["routing"] = o.routing
 	options["search_after"] = o.searchAfter
+	options["search_type"] = o.searchType
 	options["size"] = o.size
 	options["sort"] = o.sort
 	options["source_enable"] = o.sourceEnabled
 	options["sum_field"] = o.sumKey
 	options["sum_key"] = o.sumKey
+	options["terminate_after"] = o.terminateAfter
 	options["terms"] = o.terms
+	options["timeout"] = o.timeout.String()
 	return string(anchor.ToJSONFormatted(options))
 }
 
+// WithAllowNoIndices sets whether a query should fail if a wildcard expression, alias, or `_all` resolves to no
+// concrete indices. If not set, the cluster default is used.
+func WithAllowNoIndices(allow bool) func(*SearchOption) {
+	return func(o *SearchOption) {
+		o.allowNoIndices = &allow
+	}
+}
+
+// WithAllowPartialSearchResults sets whether to return partial results if there are shard request timeouts or shard
+// failures. If not set, the cluster default is used.
+func WithAllowPartialSearchResults(allow bool) func(*SearchOption) {
+	return func(o *SearchOption) {
+		o.allowPartialSearchResults = &allow
+	}
+}
+
 // WithDocvalueFields sets whether to include `docvalue` fields in search results. Default is false.
 func WithDocvalueFields(docvalueFields bool) func(*SearchOption) {
 	return func(o *SearchOption) {
@@ -171,6 +274,31 @@ func WithExcludeFields(fields ...string) func(*SearchOption) {
 	}
 }
 
+// WithExpandWildcards sets the type(s) of indices that wildcard expressions can match, where each value is one of
+// ExpandWildcardsAll, ExpandWildcardsClosed, ExpandWildcardsHidden, ExpandWildcardsNone, or ExpandWildcardsOpen. Any
+// other value will be ignored.
+func WithExpandWildcards(values ...string) func(*SearchOption) {
+	return func(o *SearchOption) {
+		for _, v := range values {
+			v = strings.ToLower(strings.TrimSpace(v))
+			for _, w := range expandWildcards {
+				if v == w {
+					o.expandWildcards = append(o.expandWildcards, v)
+					break
+				}
+			}
+		}
+	}
+}
+
+// WithIgnoreUnavailable sets whether missing or closed indices are ignored rather than causing a query to fail. If
+// not set, the cluster default is used.
+func WithIgnoreUnavailable(ignore bool) func(*SearchOption) {
+	return func(o *SearchOption) {
+		o.ignoreUnavailable = &ignore
+	}
+}
+
 // WithIncludeFields adds the field(s) to SearchOption to filter what fields are included in results.
 func WithIncludeFields(fields ...string) func(*SearchOption) {
 	return func(o *SearchOption) {
@@ -178,6 +306,14 @@ func WithIncludeFields(fields ...string) func(*SearchOption) {
 	}
 }
 
+// WithIndices adds the index, alias, or data stream name(s) to the SearchOption for targeting multiple indices in a
+// single query. Names may include wildcard (`*`) expressions.
+func WithIndices(indices ...string) func(*SearchOption) {
+	return func(o *SearchOption) {
+		o.indices = append(o.indices, indices...)
+	}
+}
+
 // WithMatch adds a field to the SearchOption for matching results.
 func WithMatch(field string, value any, predicate string) func(*SearchOption) {
 	return func(o *SearchOption) {
@@ -203,6 +339,14 @@ func WithMatchAll(matchAll bool) func(*SearchOption) {
 	}
 }
 
+// WithPreference sets the nodes and shards used for executing a query (e.g. `_local`, `_only_nodes:<node-id>`, or a
+// custom string for routing repeated queries to the same shard copies).
+func WithPreference(preference string) func(*SearchOption) {
+	return func(o *SearchOption) {
+		o.preference = strings.TrimSpace(preference)
+	}
+}
+
 // WithQueryString adds the query string to SearchOption for matching results.
 func WithQueryString(query string) func(*SearchOption) {
 	return func(o *SearchOption) {
@@ -219,6 +363,24 @@ func WithQueryStringFields(fields ...string) func(*SearchOption) {
 	}
 }
 
+// WithRequestCache sets whether the shard request cache is used for a query. If not set, the index setting is used.
+func WithRequestCache(enable bool) func(*SearchOption) {
+	return func(o *SearchOption) {
+		o.requestCache = &enable
+	}
+}
+
+// WithRouting adds the custom routing value(s) used for directing a query to specific shards.
+func WithRouting(routing ...string) func(*SearchOption) {
+	return func(o *SearchOption) {
+		for _, r := range routing {
+			if r = strings.TrimSpace(r); r != "" {
+				o.routing = append(o.routing, r)
+			}
+		}
+	}
+}
+
 // WithSearchAfter sets the fields that should be used for paginating results when the number of matching documents
 // exceed the maximum result size threshold of MaxResultSize. The default value is `@timestamp`.
 //
@@ -230,6 +392,20 @@ func WithSearchAfter(searchAfter ...any) func(*SearchOption) {
 	}
 }
 
+// WithSearchType sets how document scores are computed for a search query, either SearchTypeQueryThenFetch or
+// SearchTypeDFSQueryThenFetch. Any other value will be ignored.
+func WithSearchType(searchType string) func(*SearchOption) {
+	return func(o *SearchOption) {
+		searchType = strings.ToLower(strings.TrimSpace(searchType))
+		for _, t := range searchTypes {
+			if searchType == t {
+				o.searchType = searchType
+				return
+			}
+		}
+	}
+}
+
 // WithSize sets the number of results to return for a SearchOption.
 func WithSize(s int) func(*SearchOption) {
 	return func(o *SearchOption) {
@@ -283,6 +459,16 @@ func WithSum(sum string) func(*SearchOption) {
 	}
 }
 
+// WithTerminateAfter sets the maximum number of documents to collect for each shard, upon reaching which the query
+// execution will terminate early.
+func WithTerminateAfter(count int) func(*SearchOption) {
+	return func(o *SearchOption) {
+		if count > 0 {
+			o.terminateAfter = count
+		}
+	}
+}
+
 // WithTerm adds the criteria to the SearchOption for performing term queries.
 func WithTerm(field string, value any, predicate string) func(*SearchOption) {
 	return func(o *SearchOption) {
@@ -301,6 +487,16 @@ func WithTerm(field string, value any, predicate string) func(*SearchOption) {
 	}
 }
 
+// WithTimeout sets the explicit timeout for each query request. Results collected before the timeout elapses will be
+// returned.
+func WithTimeout(timeout time.Duration) func(*SearchOption) {
+	return func(o *SearchOption) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
 // PrepareSearch prepares the search Query for use in repository operations.
 func (o *SearchOption) PrepareSearch() *Query {
 	query := o.PrepareQuery()
@@ -404,3 +600,66 @@ func (o *SearchOption) PrepareQuery() *Query {
 	}
 	return query
 }
+
+func (o *SearchOption) prepareSearchRequest(index []string, query *Query) opensearchapi.SearchRequest {
+	request := opensearchapi.SearchRequest{
+		Index:                     index,
+		Body:                      query.Reader(),
+		AllowNoIndices:            copyBool(o.allowNoIndices),
+		AllowPartialSearchResults: copyBool(o.allowPartialSearchResults),
+		ExpandWildcards:           strings.Join(o.expandWildcards, ","),
+		IgnoreUnavailable:         copyBool(o.ignoreUnavailable),
+		Preference:                o.preference,
+		RequestCache:              copyBool(o.requestCache),
+		Routing:                   copyStrs(o.routing),
+		SearchType:                o.searchType,
+		Timeout:                   o.timeout,
+	}
+
+	if o.terminateAfter > 0 {
+		terminateAfter := o.terminateAfter
+		request.TerminateAfter = &terminateAfter
+	}
+	return request
+}
+
+func (o *SearchOption) prepareCountRequest(index []string, query *Query) opensearchapi.CountRequest {
+	request := opensearchapi.CountRequest{
+		Index:             index,
+		Body:              query.Reader(),
+		AllowNoIndices:    copyBool(o.allowNoIndices),
+		ExpandWildcards:   strings.Join(o.expandWildcards, ","),
+		IgnoreUnavailable: copyBool(o.ignoreUnavailable),
+		Preference:        o.preference,
+		Routing:           copyStrs(o.routing),
+	}
+
+	if o.terminateAfter > 0 {
+		terminateAfter := o.terminateAfter
+		request.TerminateAfter = &terminateAfter
+	}
+	return request
+}
+
+func (o *SearchOption) prepareDeleteByQueryRequest(index []string, query *Query) opensearchapi.DeleteByQueryRequest {
+	refresh := true
+	request := opensearchapi.DeleteByQueryRequest{
+		Index:             index,
+		Body:              query.Reader(),
+		AllowNoIndices:    copyBool(o.allowNoIndices),
+		ExpandWildcards:   strings.Join(o.expandWildcards, ","),
+		IgnoreUnavailable: copyBool(o.ignoreUnavailable),
+		Preference:        o.preference,
+		Refresh:           &refresh,
+		RequestCache:      copyBool(o.requestCache),
+		Routing:           copyStrs(o.routing),
+		SearchTimeout:     o.timeout,
+		SearchType:        o.searchType,
+	}
+
+	if o.terminateAfter > 0 {
+		terminateAfter := o.terminateAfter
+		request.TerminateAfter = &terminateAfter
+	}
+	return request
+}
@@ -0,0 +1,14 @@
+package repository
+
+// TemplateOption is a container for options used for applying templates.
+type TemplateOption struct {
+	dryRun bool
+}
+
+// WithTemplateDryRun sets whether to only report the templates that would be created, updated, or left unchanged
+// without applying them. Default is false.
+func WithTemplateDryRun(dryRun bool) func(*TemplateOption) {
+	return func(o *TemplateOption) {
+		o.dryRun = dryRun
+	}
+}
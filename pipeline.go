@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+
+	json "github.com/json-iterator/go"
+)
+
+// Pipeline represents an OpenSearch ingest pipeline.
+type Pipeline struct {
+	ID          string           `json:"-"`
+	Description string           `json:"description,omitempty"`
+	Processors  []map[string]any `json:"processors"`
+	OnFailure   []map[string]any `json:"on_failure,omitempty"`
+	Version     int              `json:"version,omitempty"`
+	Meta        map[string]any   `json:"_meta,omitempty"`
+}
+
+// String returns a string representation of the Pipeline.
+func (p *Pipeline) String() string {
+	return string(anchor.ToJSONFormatted(map[string]any{
+		"id":       p.ID,
+		"pipeline": p,
+	}))
+}
+
+// ProcessorResult is the result of running a Document through a single processor of an ingest pipeline.
+type ProcessorResult struct {
+	Type     string
+	Tag      string
+	Status   string
+	Document *Document
+	Error    error
+}
+
+// SimulateResult is the result of running a Document through an ingest pipeline, which contains the output of each
+// processor in the order they were executed.
+type SimulateResult struct {
+	Document   *Document
+	Processors []*ProcessorResult
+}
+
+// Output returns the Document produced by the last processor that ran successfully, or nil if no processor did.
+func (r *SimulateResult) Output() *Document {
+	for i := len(r.Processors) - 1; i >= 0; i-- {
+		if r.Processors[i].Error == nil && r.Processors[i].Document != nil {
+			return r.Processors[i].Document
+		}
+	}
+	return nil
+}
+
+// Err returns the error from the first processor that failed, if any.
+func (r *SimulateResult) Err() error {
+	for _, p := range r.Processors {
+		if p.Error != nil {
+			return p.Error
+		}
+	}
+	return nil
+}
+
+// String returns a string representation of the SimulateResult.
+func (r *SimulateResult) String() string {
+	processors := make([]map[string]any, len(r.Processors))
+	for i, p := range r.Processors {
+		pm := map[string]any{
+			"type":   p.Type,
+			"status": p.Status,
+		}
+
+		if p.Tag != "" {
+			pm["tag"] = p.Tag
+		}
+
+		if p.Document != nil {
+			pm["document"] = p.Document.Content()
+		}
+
+		if p.Error != nil {
+			pm["error"] = p.Error.Error()
+		}
+		processors[i] = pm
+	}
+
+	rm := map[string]any{"processors": processors}
+	if r.Document != nil {
+		rm["document"] = r.Document.Content()
+	}
+	return string(anchor.ToJSONFormatted(rm))
+}
+
+// PipelineService provides operations for managing OpenSearch ingest pipelines.
+type PipelineService struct {
+	client *opensearch.Client
+}
+
+// Pipelines returns the PipelineService for the Repository.
+func (r *Repository) Pipelines() *PipelineService {
+	return &PipelineService{client: r.client}
+}
+
+// Get retrieves the ingest pipeline with the provided ID. If the pipeline does not exist, an error wrapping
+// ErrNotFound is returned.
+func (s *PipelineService) Get(ctx context.Context, id string) (*Pipeline, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, ErrInvalid
+	}
+
+	pipelines, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pipelines) == 0 {
+		return nil, fmt.Errorf("opensearch: pipeline %s: %w", id, ErrNotFound)
+	}
+	return pipelines[0], nil
+}
+
+// List returns all ingest pipelines sorted by ID.
+func (s *PipelineService) List(ctx context.Context) ([]*Pipeline, error) {
+	pipelines, err := s.get(ctx)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return pipelines, nil
+}
+
+// Put creates or replaces the provided ingest pipeline.
+func (s *PipelineService) Put(ctx context.Context, pipeline *Pipeline) error {
+	if pipeline == nil || strings.TrimSpace(pipeline.ID) == "" {
+		return ErrInvalid
+	}
+
+	log.Debug("[opensearch] putting ingest pipeline", log.String("id", pipeline.ID))
+
+	return do(ctx, s.client, "put pipeline", opensearchapi.IngestPutPipelineRequest{
+		PipelineID: pipeline.ID,
+		Body:       bytes.NewReader(anchor.ToJSON(pipeline)),
+	}, nil)
+}
+
+// Delete deletes the ingest pipelines with the provided IDs.
+func (s *PipelineService) Delete(ctx context.Context, ids ...string) error {
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return ErrInvalid
+		}
+
+		log.Debug("[opensearch] deleting ingest pipeline", log.String("id", id))
+
+		if err := do(ctx, s.client, "delete pipeline", opensearchapi.IngestDeletePipelineRequest{PipelineID: id}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Simulate runs the provided documents through the installed ingest pipeline with the provided ID and returns the
+// output of each processor for each Document.
+func (s *PipelineService) Simulate(ctx context.Context, id string, documents ...*Document) ([]*SimulateResult, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, ErrInvalid
+	}
+	return s.simulate(ctx, id, nil, documents...)
+}
+
+// SimulatePipeline runs the provided documents through the provided ingest pipeline without installing it and returns
+// the output of each processor for each Document.
+func (s *PipelineService) SimulatePipeline(ctx context.Context, pipeline *Pipeline, documents ...*Document) ([]*SimulateResult, error) {
+	if pipeline == nil {
+		return nil, ErrInvalid
+	}
+	return s.simulate(ctx, "", pipeline, documents...)
+}
+
+func (s *PipelineService) get(ctx context.Context, ids ...string) ([]*Pipeline, error) {
+	var e map[string]*Pipeline
+	err := do(ctx, s.client, "get pipeline", opensearchapi.IngestGetPipelineRequest{
+		PipelineID: strings.Join(ids, ","),
+	}, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	pipelines := make([]*Pipeline, 0, len(e))
+	for id, p := range e {
+		p.ID = id
+		pipelines = append(pipelines, p)
+	}
+
+	sort.Slice(pipelines, func(i, j int) bool {
+		return pipelines[i].ID < pipelines[j].ID
+	})
+	return pipelines, nil
+}
+
+func (s *PipelineService) simulate(ctx context.Context, id string, pipeline *Pipeline, documents ...*Document) ([]*SimulateResult, error) {
+	if len(documents) == 0 {
+		return nil, ErrInvalid
+	}
+
+	type doc struct {
+		Index  string          `json:"_index,omitempty"`
+		ID     string          `json:"_id,omitempty"`
+		Source json.RawMessage `json:"_source"`
+	}
+
+	body := map[string]any{}
+	if pipeline != nil {
+		body["pipeline"] = pipeline
+	}
+
+	docs := make([]doc, len(documents))
+	for i, d := range documents {
+		if len(d.Content()) == 0 {
+			return nil, ErrMalformedDocumentContent
+		}
+		docs[i] = doc{Index: d.Index(), ID: d.ID(), Source: d.Content()}
+	}
+	body["docs"] = docs
+
+	log.Trace("[opensearch] simulating ingest pipeline",
+		log.String("id", id),
+		log.Int("documents", len(documents)))
+
+	var e struct {
+		Docs []struct {
+			ProcessorResults []struct {
+				Type   string `json:"processor_type"`
+				Tag    string `json:"tag"`
+				Status string `json:"status"`
+				Doc    *doc   `json:"doc"`
+				Error  *struct {
+					Type   string `json:"type"`
+					Reason string `json:"reason"`
+				} `json:"error"`
+			} `json:"processor_results"`
+		} `json:"docs"`
+	}
+
+	verbose := true
+	err := do(ctx, s.client, "simulate pipeline", opensearchapi.IngestSimulateRequest{
+		PipelineID: id,
+		Body:       bytes.NewReader(anchor.ToJSON(body)),
+		Verbose:    &verbose,
+	}, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SimulateResult, len(e.Docs))
+	for i, d := range e.Docs {
+		result := &SimulateResult{}
+		if i < len(documents) {
+			result.Document = documents[i]
+		}
+
+		for _, pr := range d.ProcessorResults {
+			p := &ProcessorResult{
+				Type:   pr.Type,
+				Tag:    pr.Tag,
+				Status: pr.Status,
+			}
+
+			if pr.Doc != nil {
+				p.Document = NewDocument(
+					WithDocumentID(pr.Doc.ID),
+					WithIndex(pr.Doc.Index),
+					WithContent(pr.Doc.Source),
+				)
+			}
+
+			if pr.Error != nil {
+				p.Error = fmt.Errorf("%s: %s", pr.Error.Type, pr.Error.Reason)
+			}
+			result.Processors = append(result.Processors, p)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
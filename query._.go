@@ -30,6 +30,13 @@ type Query struct {
 	Aggs           map[string]any   `json:"aggs,omitempty"`
 }
 
+// Script defines a script used for modifying documents, such as during reindex and update-by-query operations.
+type Script struct {
+	Source string         `json:"source"`
+	Lang   string         `json:"lang,omitempty"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
 // AddBool adds the provided bool predicates to the Query.
 func (q *Query) AddBool(queries ...BoolQuery) {
 	boolQueries := make(map[string][]any)
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// DefaultBulkBatchSize is the maximum size in bytes of the NDJSON body of a single bulk request issued by
+// Repository.Bulk. Larger batches are split into multiple requests.
+const DefaultBulkBatchSize = DefaultFlushSize
+
+// Enumeration of the results reported by BulkResponseItem.Result.
+const (
+	BulkResultCreated  = "created"
+	BulkResultDeleted  = "deleted"
+	BulkResultNoop     = "noop"
+	BulkResultNotFound = "not_found"
+	BulkResultUpdated  = "updated"
+)
+
+// BulkResponseItem is the response for a single operation of a bulk request.
+type BulkResponseItem struct {
+	Action      string `json:"-"`
+	Index       string `json:"_index"`
+	ID          string `json:"_id"`
+	Version     int64  `json:"_version"`
+	Result      string `json:"result"`
+	SeqNo       int64  `json:"_seq_no"`
+	PrimaryTerm int64  `json:"_primary_term"`
+	Status      int    `json:"status"`
+	Error       *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error,omitempty"`
+}
+
+// err returns the BulkItemError for the BulkResponseItem, or nil if the operation succeeded.
+func (i *BulkResponseItem) err() error {
+	if i.Error == nil && i.Status < 300 {
+		return nil
+	}
+
+	e := &BulkItemError{
+		Action: i.Action,
+		Index:  i.Index,
+		ID:     i.ID,
+		Status: i.Status,
+	}
+
+	if i.Error != nil {
+		e.Type = i.Error.Type
+		e.Reason = i.Error.Reason
+	}
+	return e
+}
+
+// BulkItemResult is the result of a single BulkOperation, where Error is a *BulkItemError if the operation failed.
+type BulkItemResult struct {
+	Operation *BulkOperation
+	Response  *BulkResponseItem
+	Error     error
+}
+
+// BulkResult is a container for the results of Repository.Bulk, ordered the same as the submitted operations.
+type BulkResult struct {
+	Items []*BulkItemResult
+}
+
+// Failed returns the results of the operations that failed.
+func (r *BulkResult) Failed() []*BulkItemResult {
+	var failed []*BulkItemResult
+	for _, item := range r.Items {
+		if item.Error != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// String returns a string representation of the BulkResult.
+func (r *BulkResult) String() string {
+	items := make([]map[string]any, len(r.Items))
+	for i, item := range r.Items {
+		im := map[string]any{
+			"action":   item.Operation.Action(),
+			"response": item.Response,
+		}
+
+		if item.Error != nil {
+			im["error"] = item.Error.Error()
+		}
+		items[i] = im
+	}
+	return string(anchor.ToJSONFormatted(map[string]any{"items": items}))
+}
+
+// Bulk executes the provided operations using the `_bulk` API and returns the result of each operation in the order
+// submitted. All operations are validated before any request is issued. Operations are split into multiple requests
+// when the NDJSON body exceeds DefaultBulkBatchSize.
+//
+// An error is only returned if an operation is invalid or a request fails; failures of individual operations are
+// reported by BulkItemResult.Error. If a request fails, the results of the requests that completed before it are
+// returned along with the error.
+//
+// The affected shards are not refreshed, so use IndexService.Refresh if the changes must be immediately visible to
+// search.
+func (r *Repository) Bulk(ctx context.Context, operations ...*BulkOperation) (*BulkResult, error) {
+	if len(operations) == 0 {
+		return nil, ErrInvalid
+	}
+
+	for _, op := range operations {
+		if op == nil {
+			return nil, ErrInvalid
+		}
+
+		if err := op.Validate(); err != nil {
+			return nil, r.logQueryError(err)
+		}
+	}
+
+	result := &BulkResult{Items: make([]*BulkItemResult, 0, len(operations))}
+
+	var (
+		buf   bytes.Buffer
+		batch []*BulkOperation
+	)
+	for _, op := range operations {
+		n := buf.Len()
+		if err := op.encode(&buf); err != nil {
+			return result, r.logQueryError(fmt.Errorf("opensearch: could not encode bulk %s: %w", op.Action(), err))
+		}
+
+		if buf.Len() > DefaultBulkBatchSize && len(batch) > 0 {
+			encoded := append([]byte(nil), buf.Bytes()[n:]...)
+			buf.Truncate(n)
+
+			items, err := r.bulk(ctx, &buf, batch)
+			if err != nil {
+				return result, err
+			}
+			result.Items = append(result.Items, items...)
+
+			buf.Reset()
+			buf.Write(encoded)
+			batch = batch[:0]
+		}
+		batch = append(batch, op)
+	}
+
+	items, err := r.bulk(ctx, &buf, batch)
+	if err != nil {
+		return result, err
+	}
+	result.Items = append(result.Items, items...)
+	return result, nil
+}
+
+func (r *Repository) bulk(ctx context.Context, body *bytes.Buffer, batch []*BulkOperation) ([]*BulkItemResult, error) {
+	log.Trace("[opensearch] executing query",
+		log.Int("operations", len(batch)),
+		log.Int("bytes", body.Len()),
+		log.String("query", "bulk"))
+
+	var e struct {
+		Took   int64                          `json:"took"`
+		Errors bool                           `json:"errors"`
+		Items  []map[string]*BulkResponseItem `json:"items"`
+	}
+	err := do(ctx, r.client, "bulk", opensearchapi.BulkRequest{
+		Body: bytes.NewReader(body.Bytes()),
+	}, &e)
+	if err != nil {
+		return nil, r.logQueryError(err)
+	}
+
+	if len(e.Items) != len(batch) {
+		return nil, r.logQueryError(fmt.Errorf("opensearch: bulk response contains %d items for %d operations",
+			len(e.Items), len(batch)))
+	}
+
+	items := make([]*BulkItemResult, len(batch))
+	for i, op := range batch {
+		item := &BulkItemResult{Operation: op}
+		for action, response := range e.Items[i] {
+			response.Action = action
+			item.Response = response
+			item.Error = response.err()
+		}
+		items[i] = item
+	}
+
+	log.Trace("[opensearch] received query result",
+		log.Int64("took", e.Took),
+		log.Bool("errors", e.Errors),
+		log.Int("items", len(items)))
+
+	return items, nil
+}
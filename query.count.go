@@ -6,24 +6,27 @@ import (
 	"strings"
 
 	"github.com/transientvariable/log-go"
-
-	"github.com/opensearch-project/opensearch-go/opensearchapi"
 )
 
 // Count performs a search query for the provided index and options.
+//
+// The index may be a comma-separated list of index, alias, or data stream names, including wildcard expressions, and
+// additional targets can be provided using the option WithIndices.
 func (r *Repository) Count(ctx context.Context, index string, options ...func(*SearchOption)) (*Result, error) {
-	index = strings.TrimSpace(index)
-	if len(index) == 0 {
-		return nil, r.logQueryError(ErrMalformedIndex)
-	}
-
-	log.Trace("[opensearch] executing query", log.String("index", index), log.String("query", "count"))
-
 	so := &SearchOption{}
 	for _, option := range options {
 		option(so)
 	}
 
+	targets := so.Targets(index)
+	if len(targets) == 0 {
+		return nil, r.logQueryError(ErrMalformedIndex)
+	}
+
+	log.Trace("[opensearch] executing query",
+		log.String("index", strings.Join(targets, ",")),
+		log.String("query", "count"))
+
 	log.Trace(fmt.Sprintf("[opensearch] search options:\n%s", so))
 
 	query := so.PrepareSearch()
@@ -33,10 +36,7 @@ func (r *Repository) Count(ctx context.Context, index string, options ...func(*S
 
 	log.Trace(fmt.Sprintf("[opensearch] retrieving count for documents matching query:\n%s", query))
 
-	count, err := r.execute(ctx, opensearchapi.CountRequest{
-		Index: []string{index},
-		Body:  query.Reader(),
-	})
+	count, err := r.execute(ctx, so.prepareCountRequest(targets, query))
 	if err != nil {
 		return nil, r.logQueryError(ErrMalformedIndex)
 	}
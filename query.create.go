@@ -24,20 +24,24 @@ func (r *Repository) Create(ctx context.Context, documents ...*Document) (*Resul
 		log.Trace("[opensearch] executing query",
 			log.String("index", index),
 			log.String("id", doc.ID()),
+			log.String("routing", doc.Routing()),
+			log.String("pipeline", doc.Pipeline()),
 			log.String("query", "create"))
 
 		result, err := r.execute(ctx, opensearchapi.IndexRequest{
 			Index:      index,
 			DocumentID: doc.ID(),
 			Body:       doc.Reader(),
+			Pipeline:   doc.Pipeline(),
 			Refresh:    "true",
+			Routing:    doc.Routing(),
 		})
 		if err != nil {
 			return nil, err
 		}
 
 		indexResult.Total += result.Total
-		indexResult.Documents = append(result.Documents)
+		indexResult.Documents = append(indexResult.Documents, result.Documents...)
 	}
 	return indexResult, nil
 }
@@ -6,37 +6,67 @@ import (
 	"strings"
 
 	"github.com/transientvariable/log-go"
-
-	"github.com/opensearch-project/opensearch-go/opensearchapi"
 )
 
 // Delete removes a document from the provided OpenSearch index and options.
+//
+// The index may be a comma-separated list of index, alias, or data stream names, including wildcard expressions, and
+// additional targets can be provided using the option WithIndices.
 func (r *Repository) Delete(ctx context.Context, index string, options ...func(*SearchOption)) (*Result, error) {
-	index = strings.TrimSpace(index)
-	if len(index) == 0 {
+	so := &SearchOption{}
+	for _, option := range options {
+		option(so)
+	}
+
+	targets := so.Targets(index)
+	if len(targets) == 0 {
 		return nil, r.logQueryError(ErrMalformedIndex)
 	}
 
-	log.Trace("[opensearch] executing query", log.String("index", index), log.String("query", "delete"))
+	log.Trace("[opensearch] executing query",
+		log.String("index", strings.Join(targets, ",")),
+		log.String("query", "delete"))
+
+	log.Trace(fmt.Sprintf("[opensearch] delete options:\n%s", so))
+
+	query := so.PrepareQuery()
+	if !query.HasQuery() {
+		return &Result{}, nil
+	}
+
+	log.Trace(fmt.Sprintf("[opensearch] deleting document(s) matching query:\n%s", query))
+
+	return r.execute(ctx, so.prepareDeleteByQueryRequest(targets, query))
+}
 
+// DeleteAsync starts removing the documents matching the provided index and options, and returns a Task that can be
+// used for tracking the progress of the operation.
+func (r *Repository) DeleteAsync(ctx context.Context, index string, options ...func(*SearchOption)) (*Task, error) {
 	so := &SearchOption{}
 	for _, option := range options {
 		option(so)
 	}
 
-	log.Trace(fmt.Sprintf("[opensearch] delete options:\n%s", so))
+	targets := so.Targets(index)
+	if len(targets) == 0 {
+		return nil, r.logQueryError(ErrMalformedIndex)
+	}
 
 	query := so.PrepareQuery()
 	if !query.HasQuery() {
-		return &Result{}, nil
+		return nil, r.logQueryError(ErrInvalid)
 	}
 
-	log.Trace(fmt.Sprintf("[opensearch] deleting document(s) matching query:\n%s", query))
+	log.Trace(fmt.Sprintf("[opensearch] starting asynchronous delete for document(s) matching query:\n%s", query),
+		log.String("index", strings.Join(targets, ",")))
+
+	waitForCompletion := false
+	request := so.prepareDeleteByQueryRequest(targets, query)
+	request.WaitForCompletion = &waitForCompletion
 
-	refresh := true
-	return r.execute(ctx, opensearchapi.DeleteByQueryRequest{
-		Index:   []string{index},
-		Body:    query.Reader(),
-		Refresh: &refresh,
-	})
+	task, err := r.Tasks().submit(ctx, "delete by query", request)
+	if err != nil {
+		return nil, r.logQueryError(err)
+	}
+	return task, nil
 }
@@ -19,19 +19,25 @@ const (
 )
 
 // Search performs a search query for the provided index and options.
+//
+// The index may be a comma-separated list of index, alias, or data stream names, including wildcard expressions, and
+// additional targets can be provided using the option WithIndices. The concrete index each Document was retrieved from
+// is available using Document.Index().
 func (r *Repository) Search(ctx context.Context, index string, options ...func(*SearchOption)) (*Result, error) {
-	index = strings.TrimSpace(index)
-	if len(index) == 0 {
-		return nil, r.logQueryError(ErrMalformedIndex)
-	}
-
-	log.Trace("[opensearch] executing query", log.String("index", index), log.String("query", "search"))
-
 	so := &SearchOption{}
 	for _, option := range options {
 		option(so)
 	}
 
+	targets := so.Targets(index)
+	if len(targets) == 0 {
+		return nil, r.logQueryError(ErrMalformedIndex)
+	}
+
+	log.Trace("[opensearch] executing query",
+		log.String("index", strings.Join(targets, ",")),
+		log.String("query", "search"))
+
 	log.Trace(fmt.Sprintf("[opensearch] search options:\n%s", so))
 
 	query := so.PrepareSearch()
@@ -47,7 +53,7 @@ func (r *Repository) Search(ctx context.Context, index string, options ...func(*
 			documents []*Document
 			sum       float64
 		)
-		pages := r.paginate(ctx, index, so)
+		pages := r.paginate(ctx, targets, so)
 		for result := range pages {
 			if so.Sum() && len(result.Metrics) > 0 {
 				if v, ok := result.Metrics[so.SumKey()].(float64); ok {
@@ -68,10 +74,7 @@ func (r *Repository) Search(ctx context.Context, index string, options ...func(*
 		}
 		return result, nil
 	}
-	return r.execute(ctx, opensearchapi.SearchRequest{
-		Index: []string{index},
-		Body:  query.Reader(),
-	})
+	return r.execute(ctx, so.prepareSearchRequest(targets, query))
 }
 
 func (r *Repository) prepareCountResult(response *opensearchapi.Response) (*Result, error) {
@@ -157,7 +160,7 @@ func (r *Repository) prepareSearchResult(response *opensearchapi.Response) (*Res
 	return result, nil
 }
 
-func (r *Repository) paginate(ctx context.Context, index string, options *SearchOption) <-chan *Result {
+func (r *Repository) paginate(ctx context.Context, targets []string, options *SearchOption) <-chan *Result {
 	results := make(chan *Result)
 
 	so := options.Copy()
@@ -185,10 +188,7 @@ func (r *Repository) paginate(ctx context.Context, index string, options *Search
 
 			log.Trace(fmt.Sprintf("[opensearch] retrieving page for query:\n%s", query))
 
-			result, err := r.execute(ctx, opensearchapi.SearchRequest{
-				Index: []string{index},
-				Body:  query.Reader(),
-			})
+			result, err := r.execute(ctx, so.prepareSearchRequest(targets, query))
 			if err != nil {
 				log.Error("[opensearch] could not retrieve page",
 					log.Err(err),
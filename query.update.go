@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/transientvariable/anchor"
 	"github.com/transientvariable/log-go"
 
 	"github.com/transientvariable/repository-opensearch-go/bandaid"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
 )
 
 // Update performs an update for the provided document and options. This method does not perform an `upsert` if the
@@ -19,7 +22,10 @@ func (r *Repository) Update(ctx context.Context, doc *Document, options ...func(
 		return nil, r.logQueryError(ErrMalformedIndex)
 	}
 
-	log.Trace("[opensearch] executing query", log.String("index", index), log.String("query", "update"))
+	log.Trace("[opensearch] executing query",
+		log.String("index", index),
+		log.String("routing", doc.Routing()),
+		log.String("query", "update"))
 
 	uo := &UpdateOption{}
 	for _, option := range options {
@@ -40,5 +46,50 @@ func (r *Repository) Update(ctx context.Context, doc *Document, options ...func(
 		DocumentID: doc.ID(),
 		Body:       bytes.NewReader(query),
 		Refresh:    "true",
+		Routing:    doc.Routing(),
+	})
+}
+
+// UpdateByQueryAsync starts updating the documents matching the provided index and options using the script, and
+// returns a Task that can be used for tracking the progress of the operation.
+func (r *Repository) UpdateByQueryAsync(ctx context.Context, index string, script Script, options ...func(*SearchOption)) (*Task, error) {
+	so := &SearchOption{}
+	for _, option := range options {
+		option(so)
+	}
+
+	targets := so.Targets(index)
+	if len(targets) == 0 {
+		return nil, r.logQueryError(ErrMalformedIndex)
+	}
+
+	if strings.TrimSpace(script.Source) == "" {
+		return nil, r.logQueryError(ErrInvalid)
+	}
+
+	body := map[string]any{"script": script}
+	if query := so.PrepareQuery(); query.HasQuery() {
+		body["query"] = query.Query
+	}
+
+	log.Trace(fmt.Sprintf("[opensearch] starting asynchronous update by query:\n%s", anchor.ToJSONFormatted(body)),
+		log.String("index", strings.Join(targets, ",")))
+
+	refresh := true
+	waitForCompletion := false
+	task, err := r.Tasks().submit(ctx, "update by query", opensearchapi.UpdateByQueryRequest{
+		Index:             targets,
+		Body:              bytes.NewReader(anchor.ToJSON(body)),
+		AllowNoIndices:    copyBool(so.allowNoIndices),
+		ExpandWildcards:   strings.Join(so.expandWildcards, ","),
+		IgnoreUnavailable: copyBool(so.ignoreUnavailable),
+		Preference:        so.preference,
+		Refresh:           &refresh,
+		Routing:           copyStrs(so.routing),
+		WaitForCompletion: &waitForCompletion,
 	})
+	if err != nil {
+		return nil, r.logQueryError(err)
+	}
+	return task, nil
 }
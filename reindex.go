@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// ReindexResult represents the result of a completed reindex operation.
+type ReindexResult struct {
+	Progress
+	Took     int64            `json:"took"`
+	TimedOut bool             `json:"timed_out"`
+	Failures []map[string]any `json:"failures,omitempty"`
+}
+
+// String returns a string representation of the ReindexResult.
+func (r *ReindexResult) String() string {
+	return string(anchor.ToJSONFormatted(r))
+}
+
+// Reindex copies the documents from the source to the destination index using the provided options, and waits for
+// the operation to complete.
+//
+// The source may be a comma-separated list of index, alias, or data stream names, including wildcard expressions. If
+// any documents fail to be reindexed, the ReindexResult is returned along with an error.
+func (r *Repository) Reindex(ctx context.Context, source string, dest string, options ...func(*ReindexOption)) (*ReindexResult, error) {
+	request, err := prepareReindex(source, dest, options...)
+	if err != nil {
+		return nil, r.logQueryError(err)
+	}
+
+	waitForCompletion := true
+	request.WaitForCompletion = &waitForCompletion
+
+	result, err := reindex(ctx, r.client, request)
+	if err != nil {
+		return result, r.logQueryError(err)
+	}
+	return result, nil
+}
+
+// ReindexAsync starts copying the documents from the source to the destination index using the provided options, and
+// returns a Task that can be used for tracking the progress of the operation.
+func (r *Repository) ReindexAsync(ctx context.Context, source string, dest string, options ...func(*ReindexOption)) (*Task, error) {
+	request, err := prepareReindex(source, dest, options...)
+	if err != nil {
+		return nil, r.logQueryError(err)
+	}
+
+	waitForCompletion := false
+	request.WaitForCompletion = &waitForCompletion
+
+	task, err := r.Tasks().submit(ctx, "reindex", request)
+	if err != nil {
+		return nil, r.logQueryError(err)
+	}
+	return task, nil
+}
+
+func reindex(ctx context.Context, client *opensearch.Client, request opensearchapi.ReindexRequest) (*ReindexResult, error) {
+	var result ReindexResult
+	if err := do(ctx, client, "reindex", request, &result); err != nil {
+		return nil, err
+	}
+
+	log.Debug(fmt.Sprintf("[opensearch] completed reindex:\n%s", &result))
+
+	if len(result.Failures) > 0 {
+		return &result, fmt.Errorf("opensearch: reindex completed with %d failure(s)", len(result.Failures))
+	}
+	return &result, nil
+}
+
+func prepareReindex(source string, dest string, options ...func(*ReindexOption)) (opensearchapi.ReindexRequest, error) {
+	opts := &ReindexOption{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	so := &SearchOption{}
+	for _, opt := range opts.searchOptions {
+		opt(so)
+	}
+
+	sources := so.Targets(source)
+	dest = strings.TrimSpace(dest)
+	if len(sources) == 0 || dest == "" {
+		return opensearchapi.ReindexRequest{}, ErrMalformedIndex
+	}
+
+	log.Trace(fmt.Sprintf("[opensearch] reindex options:\n%s", opts),
+		log.String("source", strings.Join(sources, ",")),
+		log.String("dest", dest))
+
+	src := map[string]any{"index": sources}
+	if query := so.PrepareQuery(); query.HasQuery() {
+		src["query"] = query.Query
+	}
+
+	if len(so.includeFields) > 0 || len(so.excludeFields) > 0 {
+		fields := make(map[string]any)
+		if len(so.includeFields) > 0 {
+			fields["includes"] = so.includeFields
+		}
+
+		if len(so.excludeFields) > 0 {
+			fields["excludes"] = so.excludeFields
+		}
+		src["_source"] = fields
+	}
+
+	if opts.batchSize > 0 {
+		src["size"] = opts.batchSize
+	}
+
+	if opts.remote != nil {
+		remote := map[string]any{"host": opts.remote.Host}
+		if opts.remote.Username != "" {
+			remote["username"] = opts.remote.Username
+			remote["password"] = opts.remote.Password
+		}
+
+		if len(opts.remote.Headers) > 0 {
+			remote["headers"] = opts.remote.Headers
+		}
+
+		if opts.remote.SocketTimeout > 0 {
+			remote["socket_timeout"] = strconv.FormatInt(opts.remote.SocketTimeout.Milliseconds(), 10) + "ms"
+		}
+
+		if opts.remote.ConnectTimeout > 0 {
+			remote["connect_timeout"] = strconv.FormatInt(opts.remote.ConnectTimeout.Milliseconds(), 10) + "ms"
+		}
+		src["remote"] = remote
+	}
+
+	dst := map[string]any{"index": dest}
+	if opts.opType != "" {
+		dst["op_type"] = opts.opType
+	}
+
+	if opts.pipeline != "" {
+		dst["pipeline"] = opts.pipeline
+	}
+
+	body := map[string]any{
+		"source": src,
+		"dest":   dst,
+	}
+
+	if opts.conflicts != "" {
+		body["conflicts"] = opts.conflicts
+	}
+
+	if opts.maxDocs > 0 {
+		body["max_docs"] = opts.maxDocs
+	}
+
+	if opts.script != nil {
+		body["script"] = opts.script
+	}
+
+	request := opensearchapi.ReindexRequest{
+		Body:    bytes.NewReader(anchor.ToJSON(body)),
+		Slices:  opts.slices,
+		Timeout: opts.timeout,
+	}
+
+	if opts.refresh {
+		refresh := true
+		request.Refresh = &refresh
+	}
+
+	if opts.requestsPerSecond > 0 {
+		requestsPerSecond := opts.requestsPerSecond
+		request.RequestsPerSecond = &requestsPerSecond
+	}
+	return request, nil
+}
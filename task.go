@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/log-go"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+
+	json "github.com/json-iterator/go"
+)
+
+const (
+	// TaskIndex is the name of the system index that stores the results of tasks executed asynchronously.
+	TaskIndex = ".tasks"
+
+	taskPollIntervalInitial = 500 * time.Millisecond
+	taskPollIntervalMax     = 30 * time.Second
+)
+
+// Progress represents the progress of an operation that processes documents in batches, such as reindex,
+// update-by-query, and delete-by-query.
+type Progress struct {
+	Total             int     `json:"total"`
+	Created           int     `json:"created"`
+	Updated           int     `json:"updated"`
+	Deleted           int     `json:"deleted"`
+	Batches           int     `json:"batches"`
+	VersionConflicts  int     `json:"version_conflicts"`
+	Noops             int     `json:"noops"`
+	ThrottledMillis   int64   `json:"throttled_millis"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Retries           struct {
+		Bulk   int `json:"bulk"`
+		Search int `json:"search"`
+	} `json:"retries"`
+}
+
+// Processed returns the number of documents that have been processed so far.
+func (p Progress) Processed() int {
+	return p.Created + p.Updated + p.Deleted + p.VersionConflicts + p.Noops
+}
+
+// TaskStatus represents the status of a Task.
+type TaskStatus struct {
+	ID          string          `json:"id"`
+	Action      string          `json:"action"`
+	Description string          `json:"description,omitempty"`
+	Completed   bool            `json:"completed"`
+	Cancelled   bool            `json:"cancelled,omitempty"`
+	StartTime   time.Time       `json:"start_time"`
+	RunningTime time.Duration   `json:"running_time"`
+	Progress    Progress        `json:"progress"`
+	Response    json.RawMessage `json:"response,omitempty"`
+	Error       map[string]any  `json:"error,omitempty"`
+}
+
+// String returns a string representation of the TaskStatus.
+func (s *TaskStatus) String() string {
+	return string(anchor.ToJSONFormatted(s))
+}
+
+// taskInfo is the representation of a task used by the `_tasks` API.
+type taskInfo struct {
+	Node        string   `json:"node"`
+	ID          int64    `json:"id"`
+	Action      string   `json:"action"`
+	Description string   `json:"description"`
+	StartTime   int64    `json:"start_time_in_millis"`
+	RunningTime int64    `json:"running_time_in_nanos"`
+	Cancelled   bool     `json:"cancelled"`
+	Status      Progress `json:"status"`
+}
+
+// taskResult is the representation of a task result used by the `_tasks` API and stored in the TaskIndex.
+type taskResult struct {
+	Completed bool            `json:"completed"`
+	Task      taskInfo        `json:"task"`
+	Response  json.RawMessage `json:"response"`
+	Error     map[string]any  `json:"error"`
+}
+
+func (r taskResult) status() *TaskStatus {
+	s := r.Task.status()
+	s.Completed = r.Completed
+	s.Response = r.Response
+	s.Error = r.Error
+	return s
+}
+
+func (t taskInfo) status() *TaskStatus {
+	return &TaskStatus{
+		ID:          fmt.Sprintf("%s:%d", t.Node, t.ID),
+		Action:      t.Action,
+		Description: t.Description,
+		Cancelled:   t.Cancelled,
+		StartTime:   time.UnixMilli(t.StartTime).UTC(),
+		RunningTime: time.Duration(t.RunningTime),
+		Progress:    t.Status,
+	}
+}
+
+// TaskService provides operations for managing long-running operations executing asynchronously on the cluster.
+type TaskService struct {
+	client *opensearch.Client
+}
+
+// Tasks returns the TaskService for the Repository.
+func (r *Repository) Tasks() *TaskService {
+	return &TaskService{client: r.client}
+}
+
+// Task returns a Task handle for the provided task ID.
+func (s *TaskService) Task(id string) *Task {
+	return newTask(s.client, id)
+}
+
+// List returns the status of the currently running tasks, optionally filtered by action name (e.g. `*reindex`,
+// `*byquery`, `*forcemerge`).
+func (s *TaskService) List(ctx context.Context, actions ...string) ([]*TaskStatus, error) {
+	detailed := true
+	var e struct {
+		Tasks []taskInfo `json:"tasks"`
+	}
+	err := do(ctx, s.client, "list tasks", opensearchapi.TasksListRequest{
+		Actions:  actions,
+		Detailed: &detailed,
+		GroupBy:  "none",
+	}, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*TaskStatus, 0, len(e.Tasks))
+	for _, t := range e.Tasks {
+		tasks = append(tasks, t.status())
+	}
+	return tasks, nil
+}
+
+// Get retrieves the current status of the task with the provided ID.
+func (s *TaskService) Get(ctx context.Context, id string) (*TaskStatus, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, ErrInvalid
+	}
+
+	var e taskResult
+	if err := do(ctx, s.client, "get task", opensearchapi.TasksGetRequest{TaskID: id}, &e); err != nil {
+		return nil, err
+	}
+
+	status := e.status()
+	status.ID = id
+	return status, nil
+}
+
+// Cancel requests cancellation of the task with the provided ID. Cancellation is cooperative, so the task may continue
+// to run for a short period of time.
+func (s *TaskService) Cancel(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrInvalid
+	}
+
+	log.Info("[opensearch] cancelling task", log.String("id", id))
+
+	return do(ctx, s.client, "cancel task", opensearchapi.TasksCancelRequest{TaskID: id}, nil)
+}
+
+// Result retrieves the stored result of a task that was executed asynchronously from the TaskIndex. If the task has
+// not completed, or its result has been removed, an error wrapping ErrNotFound is returned.
+func (s *TaskService) Result(ctx context.Context, id string) (*TaskStatus, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, ErrInvalid
+	}
+
+	var e struct {
+		Source taskResult `json:"_source"`
+	}
+	err := do(ctx, s.client, "get task result", opensearchapi.GetRequest{
+		Index:      TaskIndex,
+		DocumentID: id,
+	}, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	status := e.Source.status()
+	status.ID = id
+	return status, nil
+}
+
+// Wait polls the status of the task with the provided ID using exponential backoff until it completes or the context
+// is done. If the task completed with an error or its response reports failures, such as the version conflicts or
+// mapping errors of a reindex, the final TaskStatus is returned along with a *TaskError. If polling stops before the
+// task completes, the last TaskStatus is returned along with an error wrapping the cause, e.g. ErrNotFound or the
+// context error. See TaskService.Watch.
+func (s *TaskService) Wait(ctx context.Context, id string) (*TaskStatus, error) {
+	var status *TaskStatus
+	err := s.watch(ctx, id, func(st *TaskStatus) error {
+		status = st
+		return nil
+	})
+	if err != nil {
+		return status, fmt.Errorf("opensearch: could not retrieve status for task %s: %w", id, err)
+	}
+
+	if err := newTaskError(status); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+// Watch streams the status of the task with the provided ID, polling using exponential backoff. Transient errors
+// retrieving the status, such as network errors or a 503 response, are retried using the same backoff. The returned
+// channel is closed once the task completes, the status cannot be retrieved due to an error that is not transient
+// (e.g. the task exists neither in the task list nor in the TaskIndex), or the context is done.
+func (s *TaskService) Watch(ctx context.Context, id string) <-chan *TaskStatus {
+	statuses := make(chan *TaskStatus)
+
+	go func() {
+		defer close(statuses)
+
+		err := s.watch(ctx, id, func(status *TaskStatus) error {
+			select {
+			case statuses <- status:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Error("[opensearch] could not retrieve task status", log.Err(err), log.String("id", id))
+		}
+	}()
+	return statuses
+}
+
+// watch polls the status of the task with the provided ID and calls fn with each status until the task completes, fn
+// returns an error, the status cannot be retrieved due to an error that is not transient, or the context is done. The
+// error that ended polling is returned, or nil if the task completed.
+func (s *TaskService) watch(ctx context.Context, id string, fn func(*TaskStatus) error) error {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = taskPollIntervalInitial
+	b.MaxInterval = taskPollIntervalMax
+	b.MaxElapsedTime = 0
+
+	for {
+		status, err := s.Get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			status, err = s.Result(ctx, id)
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && !transient(err):
+			return err
+		case err != nil:
+			log.Warn("[opensearch] could not retrieve task status, retrying", log.Err(err), log.String("id", id))
+		default:
+			log.Trace("[opensearch] retrieved task status",
+				log.String("id", id),
+				log.Bool("completed", status.Completed),
+				log.Int("processed", status.Progress.Processed()),
+				log.Int("total", status.Progress.Total))
+
+			if err := fn(status); err != nil {
+				return err
+			}
+
+			if status.Completed {
+				return nil
+			}
+		}
+
+		timer := time.NewTimer(b.NextBackOff())
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// transient returns whether the error may not reoccur when the request is retried, which is the case for errors
+// executing the request (e.g. network errors) and responses with status 408, 429, or 5xx.
+func transient(err error) bool {
+	if errors.Is(err, ErrInvalid) {
+		return false
+	}
+
+	var re *ResponseError
+	if !errors.As(err, &re) {
+		return true
+	}
+
+	switch re.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return re.StatusCode >= http.StatusInternalServerError
+}
+
+// submit executes the request, which must have `wait_for_completion` set to false, and returns a Task handle for the
+// operation that was started.
+func (s *TaskService) submit(ctx context.Context, operation string, request opensearchapi.Request) (*Task, error) {
+	var e struct {
+		Task string `json:"task"`
+	}
+	if err := do(ctx, s.client, operation, request, &e); err != nil {
+		return nil, err
+	}
+
+	if e.Task == "" {
+		return nil, fmt.Errorf("opensearch: %s response did not include a task ID", operation)
+	}
+
+	log.Debug("[opensearch] started task", log.String("operation", operation), log.String("id", e.Task))
+
+	return newTask(s.client, e.Task), nil
+}
+
+// Task is a handle for a long-running operation executing asynchronously on the cluster.
+type Task struct {
+	id    string
+	tasks *TaskService
+}
+
+func newTask(client *opensearch.Client, id string) *Task {
+	return &Task{
+		id:    strings.TrimSpace(id),
+		tasks: &TaskService{client: client},
+	}
+}
+
+// ID returns the Task ID in the format `<node_id>:<task_number>`.
+func (t *Task) ID() string {
+	return t.id
+}
+
+// Cancel requests cancellation of the Task.
+func (t *Task) Cancel(ctx context.Context) error {
+	return t.tasks.Cancel(ctx, t.id)
+}
+
+// Status retrieves the current status of the Task.
+func (t *Task) Status(ctx context.Context) (*TaskStatus, error) {
+	return t.tasks.Get(ctx, t.id)
+}
+
+// Wait waits for the Task to complete. See TaskService.Wait.
+func (t *Task) Wait(ctx context.Context) (*TaskStatus, error) {
+	return t.tasks.Wait(ctx, t.id)
+}
+
+// Watch streams the status of the Task until it completes. See TaskService.Watch.
+func (t *Task) Watch(ctx context.Context) <-chan *TaskStatus {
+	return t.tasks.Watch(ctx, t.id)
+}
+
+// String returns a string representation of the Task.
+func (t *Task) String() string {
+	return t.id
+}
@@ -4,36 +4,115 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/transientvariable/anchor"
 
 	json "github.com/json-iterator/go"
+	"gopkg.in/yaml.v3"
 )
 
 const (
-	TemplateDirNameECS   = "ecs"
-	TemplateDirNameIndex = "index"
+	TemplateDirNameECS      = "ecs"
+	TemplateDirNameIndex    = "index"
+	TemplateDirNameISM      = "ism"
+	TemplateDirNameLegacy   = "legacy"
+	TemplateDirNamePipeline = "pipeline"
 
 	TemplateNameFormatECS = "%s_%s_%s"
 
+	FieldComposedOf      = "composed_of"
 	FieldDataStream      = "data_stream"
 	FieldECSVersion      = "ecs_version"
+	FieldIndexPatterns   = "index_patterns"
 	FieldMeta            = "_meta"
+	FieldPolicy          = "policy"
+	FieldProcessors      = "processors"
 	FieldTemplatePath    = "path"
 	FieldTemplateName    = "name"
 	FieldTemplateVersion = "version"
+
+	TemplateTypeComponent = "component_template"
+	TemplateTypeIndex     = "index_template"
+	TemplateTypeLegacy    = "legacy_template"
+	TemplateTypePipeline  = "ingest_pipeline"
+	TemplateTypePolicy    = "ism_policy"
+
+	TemplateActionCreate = "create"
+	TemplateActionUpdate = "update"
+	TemplateActionNone   = "none"
 )
 
 // Template ...
 type Template struct {
-	content    []byte
-	dataStream map[string]any
-	ecsVersion string
-	path       string
-	version    int
+	composedOf   []string
+	content      []byte
+	dataStream   map[string]any
+	ecsVersion   string
+	path         string
+	templateType string
+	version      int
+}
+
+// TemplateChange describes the change applied to the cluster for a single template, or the change that would be
+// applied in dry-run mode.
+type TemplateChange struct {
+	Name             string `json:"name"`
+	Path             string `json:"path"`
+	Type             string `json:"type"`
+	Action           string `json:"action"`
+	Version          int    `json:"version,omitempty"`
+	InstalledVersion int    `json:"installed_version,omitempty"`
+}
+
+func newTemplateChange(templateType string, template *Template, installedVersion int, installed bool) *TemplateChange {
+	change := &TemplateChange{
+		Name:             template.Name(),
+		Path:             template.Path(),
+		Type:             templateType,
+		Action:           TemplateActionNone,
+		Version:          template.Version(),
+		InstalledVersion: installedVersion,
+	}
+
+	switch {
+	case !installed:
+		change.Action = TemplateActionCreate
+	case template.Version() > installedVersion:
+		change.Action = TemplateActionUpdate
+	}
+	return change
+}
+
+// TemplateReport is a container for the changes resulting from applying templates.
+type TemplateReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Changes []*TemplateChange `json:"changes,omitempty"`
+}
+
+// Changed returns the changes that created or updated a template.
+func (r *TemplateReport) Changed() []*TemplateChange {
+	var changed []*TemplateChange
+	for _, c := range r.Changes {
+		if c.Action != TemplateActionNone {
+			changed = append(changed, c)
+		}
+	}
+	return changed
+}
+
+// String returns a string representation of the TemplateReport.
+func (r *TemplateReport) String() string {
+	return string(anchor.ToJSONFormatted(r))
+}
+
+// ComposedOf returns the names of the component templates referenced by an index template.
+func (t *Template) ComposedOf() []string {
+	return copyStrs(t.composedOf)
 }
 
 // ECSVersion ...
@@ -60,6 +139,12 @@ func (t *Template) Path() string {
 	return t.path
 }
 
+// Type returns the type of the template determined from its content, which is one of TemplateTypeComponent,
+// TemplateTypeIndex, TemplateTypeLegacy, TemplateTypePipeline, or TemplateTypePolicy.
+func (t *Template) Type() string {
+	return t.templateType
+}
+
 // Version ...
 func (t *Template) Version() int {
 	return t.version
@@ -70,29 +155,56 @@ func (t *Template) Reader() io.Reader {
 	return bytes.NewReader(t.content)
 }
 
-// ReadTemplates ...
-func ReadTemplates(path string) ([]*Template, error) {
-	path = strings.TrimSpace(path)
-	if _, err := os.Stat(path); err != nil {
+// ReadTemplates reads the templates from the provided directory on the OS file system and its subdirectories. See
+// ReadTemplatesFS.
+func ReadTemplates(dir string) ([]*Template, error) {
+	dir = strings.TrimSpace(dir)
+	templates, err := ReadTemplatesFS(dirFS(dir), ".")
+	if err != nil {
 		return nil, err
 	}
 
-	var templates []*Template
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return nil, err
+	for _, t := range templates {
+		t.path = filepath.Join(dir, filepath.FromSlash(t.path))
+	}
+	return templates, nil
+}
+
+// ReadTemplatesFS reads the JSON (`.json`) and YAML (`.yaml`, `.yml`) templates from the provided directory of fsys
+// and its subdirectories, which allows templates to be bundled with embed.FS. YAML templates are converted to JSON.
+// Template names must be unique across subdirectories.
+func ReadTemplatesFS(fsys fs.FS, dir string) ([]*Template, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		dir = "."
 	}
 
-	for _, e := range entries {
-		p := filepath.Join(path, e.Name())
-		if !e.IsDir() && filepath.Ext(p) == ".json" {
-			t, err := read(p)
-			if err != nil {
-				return nil, err
-			}
+	var templates []*Template
+	paths := make(map[string]string)
+	err := fs.WalkDir(fsys, dir, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if e.IsDir() || !isConfigFile(p) {
+			return nil
+		}
+
+		t, err := read(fsys, p)
+		if err != nil {
+			return err
+		}
 
-			templates = append(templates, t)
+		if other, ok := paths[t.Name()]; ok {
+			return fmt.Errorf("opensearch: template %s at %s conflicts with %s: %w", t.Name(), p, other, ErrExists)
 		}
+		paths[t.Name()] = p
+
+		templates = append(templates, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return templates, nil
 }
@@ -118,8 +230,8 @@ func (t *Template) String() string {
 	return string(anchor.ToJSONFormatted(tm))
 }
 
-func read(path string) (*Template, error) {
-	f, err := os.ReadFile(strings.TrimSpace(path))
+func read(fsys fs.FS, name string) (*Template, error) {
+	f, err := readFile(fsys, name)
 	if err != nil {
 		return nil, err
 	}
@@ -127,12 +239,12 @@ func read(path string) (*Template, error) {
 	var templateFile map[string]any
 	err = json.Unmarshal(f, &templateFile)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("opensearch: could not decode template %s: %w", name, err)
 	}
 
 	template := &Template{
 		content: f,
-		path:    path,
+		path:    name,
 	}
 
 	if meta, ok := templateFile[FieldMeta].(map[string]any); ok {
@@ -145,8 +257,113 @@ func read(path string) (*Template, error) {
 		template.dataStream = ds
 	}
 
-	if version, ok := templateFile[FieldTemplateVersion].(int); ok {
-		template.version = version
+	if version, ok := templateFile[FieldTemplateVersion].(float64); ok {
+		template.version = int(version)
+	}
+
+	if composedOf, ok := templateFile[FieldComposedOf].([]any); ok {
+		for _, c := range composedOf {
+			if name, ok := c.(string); ok {
+				template.composedOf = append(template.composedOf, name)
+			}
+		}
 	}
+
+	template.templateType = templateType(templateFile)
 	return template, nil
 }
+
+// templateType returns the type of the template from its content. Templates without `index_patterns` are component
+// templates, while legacy templates are distinguished from composable index templates by declaring `mappings`,
+// `settings`, `aliases`, or `order` at the top level rather than under `template`.
+func templateType(templateFile map[string]any) string {
+	if _, ok := templateFile[FieldPolicy]; ok {
+		return TemplateTypePolicy
+	}
+
+	if _, ok := templateFile[FieldProcessors]; ok {
+		return TemplateTypePipeline
+	}
+
+	if _, ok := templateFile[FieldIndexPatterns]; !ok {
+		return TemplateTypeComponent
+	}
+
+	for _, f := range []string{"mappings", "settings", "aliases", "order"} {
+		if _, ok := templateFile[f]; ok {
+			return TemplateTypeLegacy
+		}
+	}
+	return TemplateTypeIndex
+}
+
+// orderTemplates orders the ingest pipelines, component, index, and legacy templates so each is applied after its
+// dependencies: ingest pipelines first, since templates may set them as the `default_pipeline`, then component
+// templates, followed by the index templates that reference them, followed by legacy templates. The order of
+// templates of the same type is preserved.
+func orderTemplates(templates ...*Template) ([]*Template, error) {
+	var ordered []*Template
+	for _, templateType := range []string{TemplateTypePipeline, TemplateTypeComponent, TemplateTypeIndex, TemplateTypeLegacy} {
+		for _, t := range templates {
+			if t.Type() == templateType {
+				ordered = append(ordered, t)
+			}
+		}
+	}
+
+	if len(ordered) != len(templates) {
+		for _, t := range templates {
+			switch t.Type() {
+			case TemplateTypePipeline, TemplateTypeComponent, TemplateTypeIndex, TemplateTypeLegacy:
+			default:
+				return nil, fmt.Errorf("opensearch: unexpected %s at %s: %w", t.Type(), t.Path(), ErrInvalid)
+			}
+		}
+	}
+	return ordered, nil
+}
+
+// readFile reads the named JSON or YAML file from fsys and returns its content as JSON.
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		var v any
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("opensearch: could not decode %s: %w", name, err)
+		}
+
+		b, err = json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("opensearch: could not convert %s to JSON: %w", name, err)
+		}
+	}
+	return b, nil
+}
+
+// isConfigFile returns whether the named file is a JSON or YAML file that is not hidden.
+func isConfigFile(name string) bool {
+	if strings.HasPrefix(path.Base(name), ".") {
+		return false
+	}
+
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// dirFS returns a file system rooted at the provided directory on the OS file system, where an empty directory
+// denotes the working directory.
+func dirFS(dir string) fs.FS {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		dir = "."
+	}
+	return os.DirFS(dir)
+}
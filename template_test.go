@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOrderTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []*Template
+		expected  []string
+		err       error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "dependencies first",
+			templates: []*Template{
+				{path: "legacy.json", templateType: TemplateTypeLegacy},
+				{path: "index.json", templateType: TemplateTypeIndex},
+				{path: "component.json", templateType: TemplateTypeComponent},
+				{path: "pipeline.json", templateType: TemplateTypePipeline},
+			},
+			expected: []string{"pipeline.json", "component.json", "index.json", "legacy.json"},
+		},
+		{
+			name: "order of same type preserved",
+			templates: []*Template{
+				{path: "index-b.json", templateType: TemplateTypeIndex},
+				{path: "component-b.json", templateType: TemplateTypeComponent},
+				{path: "index-a.json", templateType: TemplateTypeIndex},
+				{path: "component-a.json", templateType: TemplateTypeComponent},
+			},
+			expected: []string{"component-b.json", "component-a.json", "index-b.json", "index-a.json"},
+		},
+		{
+			name: "unexpected type",
+			templates: []*Template{
+				{path: "index.json", templateType: TemplateTypeIndex},
+				{path: "policy.json", templateType: TemplateTypePolicy},
+			},
+			err: ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := orderTemplates(tt.templates...)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error wrapping %v, got: %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			var paths []string
+			for _, tmpl := range ordered {
+				paths = append(paths, tmpl.Path())
+			}
+
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Fatalf("expected order %v, got %v", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestTemplateType(t *testing.T) {
+	tests := []struct {
+		name         string
+		templateFile map[string]any
+		expected     string
+	}{
+		{
+			name:         "ism policy",
+			templateFile: map[string]any{FieldPolicy: map[string]any{}},
+			expected:     TemplateTypePolicy,
+		},
+		{
+			name:         "ingest pipeline",
+			templateFile: map[string]any{FieldProcessors: []any{}},
+			expected:     TemplateTypePipeline,
+		},
+		{
+			name:         "component template",
+			templateFile: map[string]any{"template": map[string]any{}},
+			expected:     TemplateTypeComponent,
+		},
+		{
+			name:         "index template",
+			templateFile: map[string]any{FieldIndexPatterns: []any{"logs-*"}, "template": map[string]any{}},
+			expected:     TemplateTypeIndex,
+		},
+		{
+			name:         "legacy template",
+			templateFile: map[string]any{FieldIndexPatterns: []any{"logs-*"}, "order": 1, "settings": map[string]any{}},
+			expected:     TemplateTypeLegacy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := templateType(tt.templateFile); actual != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}